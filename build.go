@@ -0,0 +1,240 @@
+package oak
+
+import (
+	"reflect"
+	"sync"
+)
+
+// BuildOption configures a call to [Container.Build].
+type BuildOption func(*buildConfig)
+
+type buildConfig struct {
+	workers int
+}
+
+// WithParallelBuild lets [Container.Build] run up to workers singleton
+// constructors at the same time:
+//
+//	err := c.Build(oak.WithParallelBuild(runtime.GOMAXPROCS(0)))
+//
+// Singletons are grouped into levels: a singleton's level is above that of
+// every singleton it depends on, directly or through transients. The levels
+// are built one after the other, and the constructors within a level run
+// concurrently, so constructors must be safe to call from any goroutine.
+// Errors carry the same "constructing T" context as a sequential build; when
+// several constructors of a level fail, the first in build order is
+// returned. Instances are tracked for [Container.Shutdown] in the same order
+// as a sequential build, regardless of scheduling. workers must be at least
+// 1; WithParallelBuild(1) is the default sequential build.
+func WithParallelBuild(workers int) BuildOption {
+	return func(cfg *buildConfig) {
+		cfg.workers = workers
+	}
+}
+
+// buildSingletons constructs every singleton that Build must create: those
+// not registered with [WithLazy], and lazy ones they depend on. The
+// instances are tracked for Start and Shutdown in g.order, whichever way
+// they were scheduled.
+func (c *container) buildSingletons(g *buildGraph, workers int) error {
+	list := g.singletons()
+
+	instances := make(map[*provider]reflect.Value, len(list))
+	cleanups := make(map[*provider]func() error)
+	defer func() {
+		for _, p := range list {
+			if inst, ok := instances[p]; ok {
+				c.track(p, inst, cleanups[p])
+			}
+		}
+	}()
+
+	if workers == 1 {
+		for _, p := range list {
+			inst, bindings, cleanup, err := c.newSingleton(p)
+			if err != nil {
+				return err
+			}
+			c.storeSingleton(p, inst, bindings)
+			instances[p], cleanups[p] = inst, cleanup
+		}
+		return nil
+	}
+
+	for _, level := range g.levels(list) {
+		insts := make([]reflect.Value, len(level))
+		bindings := make([]map[reflect.Type]reflect.Value, len(level))
+		cleanupsOf := make([]func() error, len(level))
+		errs := make([]error, len(level))
+
+		var (
+			wg       sync.WaitGroup
+			sem      = make(chan struct{}, workers)
+			panicMu  sync.Mutex
+			panicked interface{}
+		)
+		for i, p := range level {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, p *provider) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				defer func() {
+					// Re-raised below, so a panicking constructor behaves as
+					// in a sequential build instead of killing the process.
+					if r := recover(); r != nil {
+						panicMu.Lock()
+						if panicked == nil {
+							panicked = r
+						}
+						panicMu.Unlock()
+					}
+				}()
+				insts[i], bindings[i], cleanupsOf[i], errs[i] = c.newSingleton(p)
+			}(i, p)
+		}
+		wg.Wait()
+
+		var first error
+		for i, p := range level {
+			if errs[i] != nil {
+				if first == nil {
+					first = errs[i]
+				}
+				continue
+			}
+			if insts[i].IsValid() {
+				c.storeSingleton(p, insts[i], bindings[i])
+				instances[p], cleanups[p] = insts[i], cleanupsOf[i]
+			}
+		}
+		if panicked != nil {
+			panic(panicked)
+		}
+		if first != nil {
+			return first
+		}
+	}
+	return nil
+}
+
+// newSingleton constructs the singleton p and decorates it through each of
+// its interface bindings that has decorators. It also returns the cleanup
+// function p's constructor returned, or nil. It only reads the container, so
+// Build may call it from several goroutines.
+func (c *container) newSingleton(p *provider) (reflect.Value, map[reflect.Type]reflect.Value, func() error, error) {
+	inst, cleanup, err := c.construct(p, nil)
+	if err != nil {
+		return reflect.Value{}, nil, nil, err
+	}
+
+	var bindings map[reflect.Type]reflect.Value
+	for _, k := range p.interfaces {
+		if len(c.decorators[k]) == 0 {
+			continue
+		}
+		v, err := c.decorate(k, inst, nil)
+		if err != nil {
+			err = withCleanup(err, cleanup)
+			return reflect.Value{}, nil, nil, constructError(p, err)
+		}
+		if bindings == nil {
+			bindings = make(map[reflect.Type]reflect.Value)
+		}
+		bindings[k] = v
+	}
+
+	return inst, bindings, cleanup, nil
+}
+
+// constructError returns a [ConstructorError] for the provider p that failed
+// to construct with err.
+func constructError(p *provider, err error) error {
+	return &ConstructorError{Type: p.outType, Name: p.name, Err: err, site: p.site()}
+}
+
+// storeSingleton caches the instance of the singleton p built by Build, in
+// p itself for a lazy singleton and in the container otherwise.
+func (c *container) storeSingleton(p *provider, inst reflect.Value, bindings map[reflect.Type]reflect.Value) {
+	if p.lazy != nil {
+		p.lazy.mu.Lock()
+		p.lazy.inst, p.lazy.bindings, p.lazy.done = inst, bindings, true
+		p.lazy.mu.Unlock()
+		return
+	}
+
+	c.singletons[p] = inst
+	for k, v := range bindings {
+		c.decorated[k] = v
+	}
+}
+
+// singletons returns, in g.order, the singletons Build constructs: every
+// singleton not registered with [WithLazy], and every lazy singleton one of
+// them depends on.
+func (g *buildGraph) singletons() []*provider {
+	needed := make(map[*provider]bool)
+
+	// Dependents come after their dependencies in g.order, so walking it
+	// backwards marks a provider before its dependencies are reached.
+	for i := len(g.order) - 1; i >= 0; i-- {
+		p := g.order[i]
+		if isSingleton(p) && p.lazy == nil {
+			needed[p] = true
+		}
+		if !needed[p] {
+			continue
+		}
+		for _, q := range g.deps[p] {
+			needed[q] = true
+		}
+	}
+
+	var list []*provider
+	for _, p := range g.order {
+		if needed[p] && isSingleton(p) {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// levels splits list, the singletons of g in g.order, into groups that can
+// be constructed concurrently. Every singleton's level is above that of the
+// singletons in list it depends on, directly or through other providers.
+func (g *buildGraph) levels(list []*provider) [][]*provider {
+	inList := make(map[*provider]bool, len(list))
+	for _, p := range list {
+		inList[p] = true
+	}
+
+	level := make(map[*provider]int, len(g.order))
+	for _, p := range g.order {
+		l := 0
+		for _, q := range g.deps[p] {
+			lq := level[q]
+			if inList[q] {
+				lq++
+			}
+			if lq > l {
+				l = lq
+			}
+		}
+		level[p] = l
+	}
+
+	var levels [][]*provider
+	for _, p := range list {
+		l := level[p]
+		for len(levels) <= l {
+			levels = append(levels, nil)
+		}
+		levels[l] = append(levels[l], p)
+	}
+	return levels
+}
+
+// isSingleton reports whether p is cached for the container's lifetime.
+func isSingleton(p *provider) bool {
+	return p.lifetime == Singleton
+}
@@ -0,0 +1,160 @@
+package oak
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithParallelBuild(t *testing.T) {
+	t.Run("wires the graph like a sequential build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestConfig)
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestDatabase)
+		mustRegister(t, c, newTestUserRepo)
+		mustRegister(t, c, newTestUserService)
+		if err := c.Build(WithParallelBuild(4)); err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+
+		svc, _ := Resolve[*testUserService](c)
+		repo, _ := Resolve[*testUserRepo](c)
+		db, _ := Resolve[*testDatabase](c)
+		log, _ := Resolve[*testLogger](c)
+		if svc.Repo != repo || repo.DB != db || db.Logger != log || svc.Logger != log {
+			t.Fatal("parallel build should share singletons across the graph")
+		}
+	})
+
+	t.Run("runs independent constructors concurrently", func(t *testing.T) {
+		c := New()
+		var started sync.WaitGroup
+		started.Add(2)
+		wait := func() error {
+			started.Done()
+			done := make(chan struct{})
+			go func() { started.Wait(); close(done) }()
+			select {
+			case <-done:
+				return nil
+			case <-time.After(5 * time.Second):
+				return errors.New("constructors did not overlap")
+			}
+		}
+		mustRegister(t, c, func() (*testLogger, error) { return &testLogger{}, wait() })
+		mustRegister(t, c, func() (*testConfig, error) { return &testConfig{}, wait() })
+
+		if err := c.Build(WithParallelBuild(2)); err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+	})
+
+	t.Run("bounds the number of workers", func(t *testing.T) {
+		c := New()
+		var running, peak atomic.Int32
+		ctor := func() {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+		}
+		mustRegister(t, c, func() *testLogger { ctor(); return &testLogger{} })
+		mustRegister(t, c, func() *testConfig { ctor(); return &testConfig{} })
+		mustRegister(t, c, func() *testTracer { ctor(); return &testTracer{} })
+		mustRegister(t, c, func() *testRenderer { ctor(); return &testRenderer{} })
+		mustRegister(t, c, func() *testRequestBuilder { ctor(); return &testRequestBuilder{} })
+
+		if err := c.Build(WithParallelBuild(2)); err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+		if p := peak.Load(); p > 2 {
+			t.Fatalf("expected at most 2 concurrent constructors, got %d", p)
+		}
+	})
+
+	t.Run("constructs dependencies first", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestConfig)
+		mustRegister(t, c, func(cfg *testConfig) *testLogger {
+			if cfg == nil {
+				t.Error("dependency should be constructed first")
+			}
+			return &testLogger{}
+		})
+		// A transient in between still orders the singletons.
+		mustRegister(t, c, func(log *testLogger) *testTracer { return &testTracer{} }, WithLifetime(Transient))
+		mustRegister(t, c, func(tr *testTracer, log *testLogger) *testRenderer {
+			return &testRenderer{Logger: log}
+		})
+
+		if err := c.Build(WithParallelBuild(4)); err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+	})
+
+	t.Run("reports constructor errors with context", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(*testLogger) (*testConfig, error) {
+			return nil, errors.New("boom")
+		})
+
+		err := c.Build(WithParallelBuild(4))
+		if err == nil || !strings.Contains(err.Error(), "constructing *oak.testConfig (") || !strings.HasSuffix(err.Error(), "): boom") {
+			t.Fatalf("expected constructing context, got: %v", err)
+		}
+	})
+
+	t.Run("closes in dependency order", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegister(t, c, func() *testClosable {
+			return &testClosable{Name: "db", Order: &order}
+		})
+		mustRegister(t, c, func(*testClosable) *testUnitOfWork {
+			return &testUnitOfWork{Name: "repo", Order: &order}
+		})
+		mustRegister(t, c, func(*testUnitOfWork) *testRequestHandler {
+			return &testRequestHandler{Order: &order}
+		})
+		if err := c.Build(WithParallelBuild(4)); err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+		if strings.Join(order, ",") != "handler,repo,db" {
+			t.Fatalf("expected [handler repo db], got %v", order)
+		}
+	})
+
+	t.Run("re-raises constructor panics", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() *testLogger { panic("boom") })
+
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatalf("expected panic 'boom', got %v", r)
+			}
+		}()
+		_ = c.Build(WithParallelBuild(2))
+	})
+
+	t.Run("rejects fewer than one worker", func(t *testing.T) {
+		c := New()
+		if err := c.Build(WithParallelBuild(0)); err == nil {
+			t.Fatal("expected error for zero workers")
+		}
+	})
+}
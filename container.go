@@ -4,10 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"reflect"
-	"strings"
+	"runtime"
 	"sync"
+	"sync/atomic"
 )
 
 // Container defines the interface for the dependency injection container.
@@ -20,14 +20,34 @@ type Container interface {
 
 	// RegisterNamed adds a named constructor. Named providers live in a
 	// separate namespace and are resolved via [Container.ResolveNamed] or
-	// the generic [ResolveNamed] helper.
+	// the generic [ResolveNamed] helper. Unlike [Container.Register], the
+	// default lifetime is [Transient]; pass [WithLifetime] to build a
+	// [Singleton] during Build and cache it per name, or to cache a [Scoped]
+	// instance per scope. Named singletons are closed by
+	// [Container.Shutdown] like any other.
 	RegisterNamed(name string, constructor interface{}, opts ...Option) error
 
 	// Build validates the full dependency graph — detecting missing providers
 	// and circular dependencies — and eagerly instantiates all [Singleton]
 	// providers. After Build succeeds the container is immutable; no further
 	// registrations are accepted.
-	Build() error
+	//
+	// The whole graph is checked before any constructor runs. Every missing
+	// provider, cycle and lifetime mismatch is reported, each once, joined
+	// with [errors.Join]; use [errors.Is] and [errors.As] to inspect them.
+	//
+	// If a constructor or an invoked function fails, Build closes the
+	// singletons it already created that implement [Shutdowner] or
+	// [io.Closer], in reverse order, and joins their close errors into the
+	// returned error. The container is left unbuilt, so Build may be
+	// retried.
+	//
+	// Singletons are constructed in registration order, each preceded by
+	// the dependencies it needs that are not built yet, in parameter order.
+	// The order is the same on every run and is the order [Container.Shutdown]
+	// reverses. With [WithParallelBuild], independent singletons run
+	// concurrently but are still tracked for Shutdown in that order.
+	Build(opts ...BuildOption) error
 
 	// Resolve returns the value for the given type. For [Singleton] providers
 	// the cached instance is returned; for [Transient] providers a new
@@ -40,11 +60,75 @@ type Container interface {
 	// generic [ResolveNamed] helper over calling this method directly.
 	ResolveNamed(name string, t reflect.Type) (reflect.Value, error)
 
-	// Shutdown gracefully closes all singleton providers that implement
-	// [io.Closer], in reverse dependency order (dependents are closed before
-	// their dependencies). The context controls the overall deadline; if it
-	// expires, remaining closers are skipped and the context error is
-	// included in the result.
+	// ResolveGroup returns a slice of type t holding an instance of every
+	// member of the value group, in registration order. t must be a slice
+	// type whose element type every member is assignable to. Prefer the
+	// generic [ResolveGroup] helper over calling this method directly.
+	ResolveGroup(group string, t reflect.Type) (reflect.Value, error)
+
+	// Decorate registers a decorator for an already-registered type T. The
+	// decorator must be a function taking the current T, plus any other
+	// dependencies, and returning func(T, deps...) T or
+	// func(T, deps...) (T, error). Every consumer of T receives the
+	// decorated value. Decorators for the same type stack in registration
+	// order, each wrapping the result of the previous one.
+	Decorate(decorator interface{}) error
+
+	// Invoke calls fn with its parameters resolved from the container, the
+	// same way constructor parameters are. If fn's last result is an error,
+	// Invoke returns it; other results are discarded (see the generic
+	// [Invoke] helper to keep them). After Build, the container is only
+	// locked while the arguments are resolved, so fn may block, for example
+	// serving requests until [Container.Shutdown] stops the server.
+	//
+	// Before Build, the call is recorded instead and runs at the end of
+	// [Container.Build], after every singleton has been created, in the order
+	// Invoke was called. Build validates the function's dependencies and
+	// returns the first error an invoked function reports. Those functions
+	// run while Build holds the container's lock: they must not block, and
+	// must not call back into the container.
+	Invoke(fn interface{}) error
+
+	// Providers describes every registered provider, in registration order.
+	// A provider registered with [As] appears once, listing its interfaces.
+	// See also the [Has] and [HasNamed] helpers.
+	Providers() []ProviderInfo
+
+	// Graph returns a snapshot of the registered providers and the
+	// dependencies between them, including those of decorators. It can be
+	// called before Build, for instance to inspect a graph Build rejects;
+	// dependencies without a provider appear as missing nodes.
+	Graph() *Graph
+
+	// NewScope returns a new [Scope] backed by this container. [Scoped]
+	// providers are constructed at most once per scope, while [Singleton]
+	// instances are shared with the container. Close the scope when it is no
+	// longer needed to release its [io.Closer] instances.
+	NewScope() Scope
+
+	// Start starts the singletons constructed so far, in the order they were
+	// constructed: those implementing [Starter] have Start called, then the
+	// hooks registered with [OnStart] run. Dependencies therefore start
+	// before their dependents. If a component fails to start, the components
+	// already started are stopped in reverse order and the stop errors are
+	// joined with the start error; Start may then be called again. A
+	// component whose Start or an earlier hook succeeded before one of its
+	// hooks failed is stopped first.
+	//
+	// Start returns [ErrNotBuilt] before Build and [ErrAlreadyStarted] once
+	// it has succeeded. Start and stop hooks must not call back into the
+	// container.
+	Start(ctx context.Context) error
+
+	// Shutdown first stops the components started by [Container.Start], in
+	// reverse start order, running [OnStop] hooks and then [Stopper].
+	// It then gracefully closes all singleton providers that implement
+	// [Shutdowner], which is passed ctx, or [io.Closer], in the reverse of
+	// the order Build constructed them in: dependents are closed before their
+	// dependencies, and independent singletons in reverse registration order.
+	// The context controls the overall deadline; once it expires, each
+	// remaining component and closer is skipped with an error naming its
+	// type and wrapping the context error.
 	//
 	// Shutdown is safe to call multiple times; subsequent calls return
 	// [ErrAlreadyShutdown]. It is the caller's responsibility to stop
@@ -55,13 +139,48 @@ type Container interface {
 type container struct {
 	mu sync.RWMutex
 
-	providers  map[reflect.Type]provider
-	named      map[string]provider
-	singletons map[reflect.Type]reflect.Value
-
-	// closers holds singletons that implement io.Closer, recorded in
-	// dependency order during Build. Shutdown iterates them in reverse.
-	closers []io.Closer
+	// providers maps every resolvable type to its provider. A provider
+	// registered with [As] appears under each of its types, so the same
+	// *provider may be reachable through several keys.
+	providers  map[reflect.Type]*provider
+	named      map[string]*provider
+	groups     map[string][]*provider
+	singletons map[*provider]reflect.Value
+
+	// registered lists every provider in registration order, so Build does
+	// not depend on map iteration order.
+	registered []*provider
+
+	// decorators holds the decorators registered for each type, in
+	// registration order, and decoratedTypes the types in the order their
+	// first decorator was registered. decorated caches singletons decorated
+	// through an interface binding made with [As].
+	decorators     map[reflect.Type][]*decorator
+	decoratedTypes []reflect.Type
+	decorated      map[reflect.Type]reflect.Value
+
+	// invocations holds functions passed to Invoke before Build, in call
+	// order. Build runs them once every singleton has been created.
+	invocations []*invocation
+
+	// building is set while Build runs, so that [Lazy.Get] can fail instead
+	// of waiting on the lock Build holds.
+	building atomic.Bool
+
+	// constructed holds every singleton in the order it was constructed,
+	// during Build or on first resolution for lazy singletons; Start walks
+	// it. closers holds the cleanup of those that implement Shutdowner or
+	// io.Closer, in the same order; Shutdown runs them in reverse. trackMu
+	// guards appends made by lazy singletons under the read-lock.
+	trackMu     sync.Mutex
+	constructed []component
+	closers     []closer
+
+	// lifeMu serializes Start. started holds the components Start started,
+	// in order; Shutdown stops them in reverse before closing.
+	lifeMu  sync.Mutex
+	started []component
+	running bool
 
 	built    bool
 	shutdown bool
@@ -70,9 +189,12 @@ type container struct {
 // New creates an empty [Container] ready for registration.
 func New() Container {
 	return &container{
-		providers:  make(map[reflect.Type]provider),
-		named:      make(map[string]provider),
-		singletons: make(map[reflect.Type]reflect.Value),
+		providers:  make(map[reflect.Type]*provider),
+		named:      make(map[string]*provider),
+		groups:     make(map[string][]*provider),
+		singletons: make(map[*provider]reflect.Value),
+		decorators: make(map[reflect.Type][]*decorator),
+		decorated:  make(map[reflect.Type]reflect.Value),
 	}
 }
 
@@ -102,41 +224,192 @@ func (c *container) register(name string, constructor interface{}, opts ...Optio
 		return errors.New("constructor must be a function")
 	}
 
+	cleanup, err := checkConstructorResults(typ)
+	if err != nil {
+		return err
+	}
+
+	p := &provider{
+		constructor: val,
+		lifetime:    Singleton,
+		name:        name,
+		outType:     typ.Out(0),
+		funcName:    funcName(val),
+		cleanup:     cleanup,
+	}
+	// Skip register and the Register or RegisterNamed method that called it.
+	_, p.file, p.line, _ = runtime.Caller(2)
+	if name != "" {
+		// Named providers predate their support for lifetimes and keep
+		// constructing a new instance on every resolution by default.
+		p.lifetime = Transient
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.err != nil {
+		return p.err
+	}
+
+	if p.cleanup {
+		if p.lifetime == Transient {
+			return errors.New("cleanup: transient constructors cannot return a cleanup function, only singletons and scoped ones")
+		}
+	}
+
+	if hooks := p.hooks(); len(hooks) > 0 {
+		if p.lifetime != Singleton {
+			return fmt.Errorf("lifecycle hooks: %s provider cannot have hooks, only singletons", p.lifetime)
+		}
+		for _, h := range hooks {
+			if !p.outType.AssignableTo(h.typ) {
+				return fmt.Errorf("lifecycle hooks: %s is not assignable to %s", p.outType, h.typ)
+			}
+		}
+	}
+
+	if p.lazy != nil {
+		if p.lifetime != Singleton {
+			return fmt.Errorf("lazy: %s provider cannot be lazy, only singletons", p.lifetime)
+		}
+	}
+
+	deps, err := newDependencies(typ, p.paramNames, p.paramGroups)
+	if err != nil {
+		return err
+	}
+	p.deps = deps
+
+	for _, iface := range p.interfaces {
+		if iface == nil {
+			return errors.New("as: interface type cannot be nil")
+		}
+		if iface.Kind() != reflect.Interface {
+			return fmt.Errorf("as: %s is not an interface", iface)
+		}
+		if !p.outType.Implements(iface) {
+			return fmt.Errorf("as: %s does not implement %s", p.outType, iface)
+		}
+	}
+
+	if p.group != "" {
+		if name != "" {
+			return fmt.Errorf("named provider %q: InGroup cannot be used with named providers", name)
+		}
+		if len(p.interfaces) > 0 {
+			return fmt.Errorf("group %q: As cannot be used with group providers", p.group)
+		}
+	}
+
+	if name != "" && len(p.interfaces) > 0 {
+		return fmt.Errorf("named provider %q: As cannot be used with named providers", name)
+	}
+
+	if isResultStruct(p.outType) {
+		fields, err := resultProviders(p)
+		if err != nil {
+			return err
+		}
+		return c.add(append([]*provider{p}, fields...)...)
+	}
+
+	return c.add(p)
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// checkResults verifies that the function type typ returns (T) or
+// (T, error). what names the function in the error message.
+func checkResults(what string, typ reflect.Type) error {
 	if typ.NumOut() == 0 || typ.NumOut() > 2 {
-		return errors.New("constructor must return (T) or (T, error)")
+		return fmt.Errorf("%s must return (T) or (T, error)", what)
 	}
 
 	if typ.NumOut() == 2 {
-		errType := reflect.TypeOf((*error)(nil)).Elem()
-		if !typ.Out(1).Implements(errType) {
+		if !typ.Out(1).Implements(errorType) {
 			return errors.New("second return value must implement error")
 		}
 	}
+	return nil
+}
 
-	p := provider{
-		constructor: val,
-		lifetime:    Singleton,
-		name:        name,
-		outType:     typ.Out(0),
+// checkConstructorResults verifies the results of a constructor: those
+// accepted by checkResults, or (T, func(), error) and (T, func() error,
+// error). cleanup reports whether the constructor returns a cleanup function.
+func checkConstructorResults(typ reflect.Type) (cleanup bool, err error) {
+	if typ.NumOut() > 3 {
+		return false, errors.New("constructor must return (T), (T, error) or (T, cleanup, error)")
+	}
+	if typ.NumOut() != 3 {
+		return false, checkResults("constructor", typ)
 	}
 
-	for _, opt := range opts {
-		opt(&p)
+	if !isCleanupFunc(typ.Out(1)) {
+		return false, errors.New("second of three return values must be func() or func() error")
+	}
+	if !typ.Out(2).Implements(errorType) {
+		return false, errors.New("third return value must implement error")
 	}
+	return true, nil
+}
 
-	if name != "" {
-		if _, exists := c.named[name]; exists {
-			return fmt.Errorf("%w: named %q", ErrDuplicateProvider, name)
+// isCleanupFunc reports whether t is func() or func() error.
+func isCleanupFunc(t reflect.Type) bool {
+	if t.Kind() != reflect.Func || t.NumIn() != 0 {
+		return false
+	}
+	return t.NumOut() == 0 || (t.NumOut() == 1 && t.Out(0) == errorType)
+}
+
+// add stores the providers in the container. Every type and name is checked
+// for duplicates before anything is stored, so a rejected registration leaves
+// the container unchanged.
+func (c *container) add(ps ...*provider) error {
+	types := make(map[reflect.Type]*provider)
+	names := make(map[string]*provider)
+
+	for _, p := range ps {
+		switch {
+		case p.group != "":
+		case p.name != "":
+			prev, exists := c.named[p.name]
+			if !exists {
+				prev, exists = names[p.name]
+			}
+			if exists {
+				return fmt.Errorf("%w: named %q registered by %s, already registered by %s",
+					ErrDuplicateProvider, p.name, p.site(), prev.site())
+			}
+			names[p.name] = p
+		default:
+			for _, t := range p.keys() {
+				prev, exists := c.providers[t]
+				if !exists {
+					prev, exists = types[t]
+				}
+				if exists {
+					return fmt.Errorf("%w: %s registered by %s, already registered by %s",
+						ErrDuplicateProvider, t, p.site(), prev.site())
+				}
+				types[t] = p
+			}
 		}
-		c.named[name] = p
-		return nil
 	}
 
-	outType := typ.Out(0)
-	if _, exists := c.providers[outType]; exists {
-		return fmt.Errorf("%w: %s", ErrDuplicateProvider, outType)
+	for _, p := range ps {
+		switch {
+		case p.group != "":
+			c.groups[p.group] = append(c.groups[p.group], p)
+		case p.name != "":
+			c.named[p.name] = p
+		default:
+			for _, t := range p.keys() {
+				c.providers[t] = p
+			}
+		}
 	}
-	c.providers[outType] = p
+	c.registered = append(c.registered, ps...)
 	return nil
 }
 
@@ -152,7 +425,88 @@ const (
 	visited
 )
 
-func (c *container) Build() error {
+// buildGraph is the dependency graph Build walks before constructing
+// anything.
+type buildGraph struct {
+	states map[*provider]buildState
+
+	// scoped records, for every visited provider, whether resolving it
+	// requires a [Scope], so that singletons depending on scoped providers
+	// are rejected.
+	scoped map[*provider]bool
+
+	// order lists the visited providers, each after its dependencies.
+	order []*provider
+
+	// deps holds the providers each visited provider depends on directly.
+	// [Lazy] and [Provider] edges are left out, as they are resolved later.
+	deps map[*provider][]*provider
+
+	// errs collects the problems found in the graph, in the order they are
+	// found. Those from errs[unit:] were found by the walk under way, from a
+	// named provider, an invocation or the unnamed providers.
+	errs []error
+	unit int
+
+	// lazy holds the [Lazy] and [Provider] edges of singletons. They are
+	// checked by checkLazyEdges once the whole graph is walked and scoped is
+	// complete.
+	lazy []lazyEdge
+}
+
+// lazyEdge is a deferred dependency of the singleton from, reached through
+// type t, on the provider to for type dep.
+type lazyEdge struct {
+	from *provider
+	t    reflect.Type
+	dep  reflect.Type
+	to   *provider
+}
+
+func newBuildGraph() *buildGraph {
+	return &buildGraph{
+		states: make(map[*provider]buildState),
+		scoped: make(map[*provider]bool),
+		deps:   make(map[*provider][]*provider),
+	}
+}
+
+// fail records err as a problem with the graph. The same problem reached
+// twice by one walk, such as a missing type needed by two parameters of one
+// constructor, is recorded once; two named providers or invocations sharing
+// a problem each get their own.
+func (g *buildGraph) fail(err error) {
+	msg := err.Error()
+	for _, e := range g.errs[g.unit:] {
+		if e.Error() == msg {
+			return
+		}
+	}
+	g.errs = append(g.errs, err)
+}
+
+// walk runs fn as a walk of its own, adding prefix to the problems it
+// records.
+func (g *buildGraph) walk(prefix string, fn func()) {
+	outer := g.unit
+	g.unit = len(g.errs)
+	fn()
+	for i := g.unit; i < len(g.errs); i++ {
+		g.errs[i] = fmt.Errorf("%s: %w", prefix, g.errs[i])
+	}
+	g.unit = outer
+}
+
+// err returns the recorded problems joined with [errors.Join], a single
+// problem as is, or nil.
+func (g *buildGraph) err() error {
+	if len(g.errs) == 1 {
+		return g.errs[0]
+	}
+	return errors.Join(g.errs...)
+}
+
+func (c *container) Build(opts ...BuildOption) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -160,85 +514,257 @@ func (c *container) Build() error {
 		return ErrAlreadyBuilt
 	}
 
-	states := make(map[reflect.Type]buildState)
+	cfg := buildConfig{workers: 1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.workers < 1 {
+		return fmt.Errorf("parallel build: workers must be at least 1, got %d", cfg.workers)
+	}
+
+	c.building.Store(true)
+	defer c.building.Store(false)
 
-	for t := range c.providers {
-		if err := c.buildResolve(t, states, nil); err != nil {
-			return err
+	g := newBuildGraph()
+	for _, t := range c.decoratedTypes {
+		if _, ok := c.providers[t]; !ok {
+			g.fail(fmt.Errorf("decorator for %s: %w", t, &MissingProviderError{Type: t}))
 		}
 	}
 
-	for name, p := range c.named {
-		if err := c.validateNamedProvider(name, p); err != nil {
-			return err
+	// Providers are visited in registration order and their dependencies in
+	// parameter order, so g.order, and with it the order singletons are
+	// constructed and closed in, is the same on every run. Every problem is
+	// collected before giving up, and nothing is constructed if there are
+	// any.
+	for _, p := range c.registered {
+		if p.name != "" {
+			c.validateNamedProvider(g, p.name, p)
+			continue
 		}
+		c.visit(g, p.outType, p, nil)
 	}
+	c.checkLazyEdges(g)
+
+	for _, inv := range c.invocations {
+		c.validateInvocation(g, inv)
+	}
+
+	if err := g.err(); err != nil {
+		return err
+	}
+
+	if err := c.buildSingletons(g, cfg.workers); err != nil {
+		return c.abortBuild(err)
+	}
+
+	for _, inv := range c.invocations {
+		if _, err := c.invoke(inv, nil); err != nil {
+			return c.abortBuild(err)
+		}
+	}
+	c.invocations = nil
 
 	c.built = true
 	return nil
 }
 
-// buildResolve walks the dependency graph depth-first using a local state map
-// and stack. Singletons are instantiated and cached; transients are only
-// validated.
-func (c *container) buildResolve(t reflect.Type, states map[reflect.Type]buildState, stack []reflect.Type) error {
-	switch states[t] {
-	case visiting:
-		return c.circularError(t, stack)
-	case visited:
-		return nil
+// abortBuild closes the singletons a failed Build already constructed, in
+// reverse order, and forgets them so that Build may be called again. Close
+// errors are joined with err.
+func (c *container) abortBuild(err error) error {
+	errs := append([]error{err}, runClosers(context.Background(), c.closers)...)
+
+	c.closers = nil
+	c.constructed = nil
+	c.singletons = make(map[*provider]reflect.Value)
+	c.decorated = make(map[reflect.Type]reflect.Value)
+	for _, p := range c.registered {
+		if p.lazy != nil {
+			p.lazy = &lazySingleton{}
+		}
 	}
 
-	p, ok := c.providers[t]
-	if !ok {
-		return fmt.Errorf("%w: %s", ErrProviderNotFound, t)
+	if len(errs) == 1 {
+		return err
 	}
+	return errors.Join(errs...)
+}
 
-	states[t] = visiting
+// visit walks the dependency graph depth-first from p, reached through type
+// t, recording it in g. States are tracked per provider, so a provider
+// reachable through several types is visited once. A problem is recorded
+// with g.fail and the walk goes on with the next dependency, so that Build
+// reports every problem at once. Nothing is constructed; see
+// buildSingletons.
+func (c *container) visit(g *buildGraph, t reflect.Type, p *provider, stack []reflect.Type) {
+	switch g.states[p] {
+	case visiting:
+		g.fail(c.circularError(t, stack))
+		return
+	case visited:
+		return
+	}
+
+	g.states[p] = visiting
 	stack = append(stack, t)
 
-	fnType := p.constructor.Type()
-	for i := 0; i < fnType.NumIn(); i++ {
-		if err := c.buildResolve(fnType.In(i), states, stack); err != nil {
-			return err
+	lifetime := p.lifetime
+	for _, d := range c.edges(p) {
+		targets, err := c.dependencyTargets(d)
+		if err != nil {
+			g.fail(requiredBy(err, p, stack))
+			continue
+		}
+		if d.lazy {
+			// The target is resolved on demand, not while p is built, so
+			// the edge cannot be part of a cycle and is not walked here. A
+			// singleton keeps the container as its resolver and cannot
+			// reach scoped providers, directly or through transients.
+			if lifetime == Singleton {
+				g.lazy = append(g.lazy, lazyEdge{from: p, t: t, dep: d.typ, to: targets[0]})
+			}
+			continue
 		}
+		for _, q := range targets {
+			depType := d.typ
+			if d.group != "" {
+				depType = q.outType
+			}
+			c.visit(g, depType, q, stack)
+			g.deps[p] = append(g.deps[p], q)
+			if !g.scoped[q] {
+				continue
+			}
+			if lifetime == Singleton {
+				g.fail(fmt.Errorf("%w: %s depends on %s", ErrLifetimeMismatch, t, depType))
+				continue
+			}
+			g.scoped[p] = true
+		}
+	}
+	if lifetime == Scoped {
+		g.scoped[p] = true
 	}
 
-	if p.lifetime == Singleton {
-		instance, err := c.construct(p)
-		if err != nil {
-			return fmt.Errorf("constructing %s: %w", t, err)
-		}
-		c.singletons[t] = instance
+	g.states[p] = visited
+	g.order = append(g.order, p)
+}
 
-		if closer, ok := instance.Interface().(io.Closer); ok {
-			c.closers = append(c.closers, closer)
+// checkLazyEdges rejects the singletons whose [Lazy] or [Provider]
+// dependencies require a [Scope]. Every provider has been visited by then,
+// so the target's scoped state accounts for all of its dependencies, and
+// following the edge does not report the cycles it breaks.
+func (c *container) checkLazyEdges(g *buildGraph) {
+	for _, e := range g.lazy {
+		if !g.scoped[e.to] {
+			continue
+		}
+		err := fmt.Errorf("%w: %s depends on %s", ErrLifetimeMismatch, e.t, e.dep)
+		if e.from.name == "" {
+			g.fail(err)
+			continue
 		}
+		g.walk(fmt.Sprintf("named provider %q", e.from.name), func() { g.fail(err) })
 	}
+}
 
-	states[t] = visited
-	return nil
+// validateNamedProvider walks the dependencies of the named provider p with
+// the same state as the rest of the graph, so cycles through named providers
+// are detected too. The problems found are prefixed with the provider's
+// name.
+func (c *container) validateNamedProvider(g *buildGraph, name string, p *provider) {
+	g.walk(fmt.Sprintf("named provider %q", name), func() {
+		c.visit(g, p.outType, p, nil)
+	})
 }
 
-func (c *container) validateNamedProvider(name string, p provider) error {
-	fnType := p.constructor.Type()
-	for i := 0; i < fnType.NumIn(); i++ {
-		depType := fnType.In(i)
-		if _, ok := c.providers[depType]; !ok {
-			return fmt.Errorf("named provider %q: %w: %s", name, ErrProviderNotFound, depType)
+// dependencyTargets returns the providers that satisfy d: the provider for
+// its type or name, or every member of its value group. An optional
+// dependency without a provider has no targets.
+func (c *container) dependencyTargets(d dependency) ([]*provider, error) {
+	switch {
+	case d.name != "":
+		p, ok := c.named[d.name]
+		if !ok {
+			if d.optional {
+				return nil, nil
+			}
+			return nil, &MissingProviderError{Type: d.typ, Name: d.name}
 		}
+		if !p.outType.AssignableTo(d.typ) {
+			return nil, fmt.Errorf("named provider %q returns %s, not assignable to %s", d.name, p.outType, d.typ)
+		}
+		return []*provider{p}, nil
+	case d.group == "":
+		p, ok := c.providers[d.typ]
+		if !ok {
+			if d.optional {
+				return nil, nil
+			}
+			return nil, &MissingProviderError{Type: d.typ}
+		}
+		return []*provider{p}, nil
 	}
-	return nil
+
+	members := c.groups[d.group]
+	for _, m := range members {
+		if !m.outType.AssignableTo(d.typ.Elem()) {
+			return nil, fmt.Errorf("group %q: %s is not assignable to %s", d.group, m.outType, d.typ.Elem())
+		}
+	}
+	return members, nil
 }
 
+// track records the singleton p, constructed as inst, for [Container.Start]
+// and, if it implements [Shutdowner] or [io.Closer] or has cleanups, for
+// [Container.Shutdown]. cleanup is the function its constructor returned, or
+// nil. Closers run in reverse, so for one instance the cleanups registered
+// with [WithCleanup] run first, then its own closer, then cleanup.
+func (c *container) track(p *provider, inst reflect.Value, cleanup func() error) {
+	c.trackMu.Lock()
+	defer c.trackMu.Unlock()
+
+	c.constructed = append(c.constructed, component{p: p, inst: inst})
+	if cleanup != nil {
+		c.closers = append(c.closers, closer{
+			p:  p,
+			fn: func(context.Context) error { return cleanup() },
+		})
+	}
+	if cl, ok := newCloser(p, inst); ok {
+		c.closers = append(c.closers, cl)
+	}
+	for _, h := range p.cleanups {
+		h := h
+		c.closers = append(c.closers, closer{
+			p:  p,
+			fn: func(ctx context.Context) error { return h.fn(ctx, inst) },
+		})
+	}
+}
+
+// circularError returns a [CycleError] for the type t, reached again through
+// stack.
 func (c *container) circularError(t reflect.Type, stack []reflect.Type) error {
-	chain := make([]string, len(stack)+1)
-	for i, s := range stack {
-		chain[i] = s.String()
+	path := make([]reflect.Type, len(stack)+1)
+	copy(path, stack)
+	path[len(stack)] = t
+	return &CycleError{Path: path}
+}
+
+// requiredBy adds the provider p, reached through stack, to err returned by
+// dependencyTargets for one of p's dependencies.
+func requiredBy(err error, p *provider, stack []reflect.Type) error {
+	missing, ok := err.(*MissingProviderError)
+	if !ok {
+		return fmt.Errorf("%w (required by %s)", err, p.site())
 	}
-	chain[len(stack)] = t.String()
 
-	return fmt.Errorf("%w: %s", ErrCircularDependency, strings.Join(chain, " -> "))
+	missing.RequiredBy = p.outType
+	missing.Path = append(append([]reflect.Type(nil), stack...), missing.Type)
+	missing.site = p.site()
+	return missing
 }
 
 // ---------------------------------------------------------------------------
@@ -259,16 +785,9 @@ func (c *container) Shutdown(ctx context.Context) error {
 
 	c.shutdown = true
 
-	var errs []error
-	for i := len(c.closers) - 1; i >= 0; i-- {
-		if err := ctx.Err(); err != nil {
-			errs = append(errs, err)
-			break
-		}
-		if err := c.closers[i].Close(); err != nil {
-			errs = append(errs, err)
-		}
-	}
+	errs := stopComponents(ctx, c.started)
+	c.started = nil
+	errs = append(errs, runClosers(ctx, c.closers)...)
 
 	return errors.Join(errs...)
 }
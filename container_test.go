@@ -3,8 +3,10 @@ package oak
 import (
 	"context"
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 // ---------------------------------------------------------------------------
@@ -187,6 +189,121 @@ func TestBuild(t *testing.T) {
 		}
 	})
 
+	t.Run("reports every problem before constructing", func(t *testing.T) {
+		c := New()
+		constructed := false
+		mustRegister(t, c, func() *testConfig { constructed = true; return &testConfig{} })
+		mustRegister(t, c, newTestDatabase) // needs *testLogger
+		mustRegister(t, c, newTestCircA)
+		mustRegister(t, c, newTestCircB)
+		mustRegister(t, c, newTestCircC)
+		mustRegister(t, c, func(*testTracer) *testRenderer { return &testRenderer{} })
+		mustRegisterNamed(t, c, "orders", newTestOrderService, WithParamName(0, "audit"))
+		mustRegister(t, c, func(*testLogger) *testUserRepo { return &testUserRepo{} })
+
+		err := c.Build()
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			t.Fatalf("expected joined errors, got: %v", err)
+		}
+		errs := joined.Unwrap()
+		if len(errs) != 5 {
+			t.Fatalf("expected 5 problems, got %d: %v", len(errs), err)
+		}
+
+		var missing *MissingProviderError
+		if !errors.As(errs[0], &missing) || missing.Type != reflect.TypeOf(&testLogger{}) {
+			t.Fatalf("expected missing *testLogger first, got: %v", errs[0])
+		}
+		if !errors.Is(errs[1], ErrCircularDependency) {
+			t.Fatalf("expected the cycle second, got: %v", errs[1])
+		}
+		if !errors.As(errs[2], &missing) || missing.Type != reflect.TypeOf(&testTracer{}) {
+			t.Fatalf("expected missing *testTracer third, got: %v", errs[2])
+		}
+		if !strings.HasPrefix(errs[3].Error(), `named provider "orders": provider not found: named "audit"`) {
+			t.Fatalf("expected the named provider's problem fourth, got: %v", errs[3])
+		}
+		if !errors.As(errs[4], &missing) || missing.RequiredBy != reflect.TypeOf(&testUserRepo{}) {
+			t.Fatalf("expected missing *testLogger for the repo last, got: %v", errs[4])
+		}
+		if constructed {
+			t.Fatal("no constructor should run when the graph is invalid")
+		}
+	})
+
+	t.Run("reports a problem once", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func(*testLogger, *testLogger) *testConfig { return &testConfig{} })
+		mustRegister(t, c, func(*testConfig) *testTracer { return &testTracer{} })
+
+		err := c.Build()
+		var missing *MissingProviderError
+		if !errors.As(err, &missing) {
+			t.Fatalf("expected *MissingProviderError, got: %v", err)
+		}
+		if _, joined := err.(interface{ Unwrap() []error }); joined {
+			t.Fatalf("expected a single problem, got: %v", err)
+		}
+	})
+
+	t.Run("reports a shared problem for each named provider", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestConfig, WithLifetime(Scoped))
+		newDB := func(*testConfig) *testDatabase { return &testDatabase{} }
+		mustRegisterNamed(t, c, "primary", newDB, WithLifetime(Singleton))
+		mustRegisterNamed(t, c, "replica", newDB, WithLifetime(Singleton))
+		for _, name := range []string{"a", "b", "c"} {
+			mustRegisterNamed(t, c, name, newTestOrderService) // needs *testLogger
+		}
+
+		err := c.Build()
+		if !errors.Is(err, ErrLifetimeMismatch) || !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected both kinds of problems, got: %v", err)
+		}
+		for _, prefix := range []string{`"primary"`, `"replica"`, `"a"`, `"b"`, `"c"`} {
+			if !strings.Contains(err.Error(), "named provider "+prefix+": ") {
+				t.Fatalf("expected a problem for %s, got: %v", prefix, err)
+			}
+		}
+		if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 5 {
+			t.Fatalf("expected 5 problems, got %d: %v", n, err)
+		}
+	})
+
+	t.Run("reports every invocation problem", func(t *testing.T) {
+		c := New()
+		if err := c.Invoke(func(*testLogger, *testConfig) {}); err != nil {
+			t.Fatalf("Invoke: %v", err)
+		}
+
+		err := c.Build()
+		msg := err.Error()
+		if !strings.Contains(msg, "provider not found: *oak.testLogger") || !strings.Contains(msg, "provider not found: *oak.testConfig") {
+			t.Fatalf("expected both missing types, got: %v", err)
+		}
+		if strings.Count(msg, "invoke func(") != 2 {
+			t.Fatalf("expected each problem prefixed with the function, got: %v", err)
+		}
+	})
+
+	t.Run("reports a shared problem for each invocation", func(t *testing.T) {
+		c := New()
+		for i := 0; i < 2; i++ {
+			if err := c.Invoke(func(*testConfig) {}); err != nil {
+				t.Fatalf("Invoke: %v", err)
+			}
+		}
+		if err := c.Invoke(func(*testConfig) error { return nil }); err != nil {
+			t.Fatalf("Invoke: %v", err)
+		}
+
+		err := c.Build()
+		if got := strings.Count(err.Error(), "provider not found: *oak.testConfig"); got != 3 {
+			t.Fatalf("expected the problem once per function, got: %v", err)
+		}
+	})
+
 	t.Run("circular dependency detected", func(t *testing.T) {
 		c := New()
 		mustRegister(t, c, newTestCircA)
@@ -226,6 +343,97 @@ func TestBuild(t *testing.T) {
 		}
 	})
 
+	t.Run("failure closes constructed singletons in reverse", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegister(t, c, func() *testClosable {
+			return &testClosable{Name: "db", Order: &order}
+		})
+		mustRegister(t, c, func(*testClosable) *testUnitOfWork {
+			return &testUnitOfWork{Name: "cache", Order: &order}
+		})
+		mustRegister(t, c, func(*testUnitOfWork) (*testConfig, error) {
+			return nil, errors.New("connection failed")
+		})
+
+		err := c.Build()
+		if err == nil || !strings.Contains(err.Error(), "connection failed") {
+			t.Fatalf("expected 'connection failed' in error, got: %v", err)
+		}
+		if strings.Join(order, ",") != "cache,db" {
+			t.Fatalf("expected [cache db], got %v", order)
+		}
+		if err := c.Shutdown(context.Background()); !errors.Is(err, ErrNotBuilt) {
+			t.Fatalf("expected ErrNotBuilt, got: %v", err)
+		}
+	})
+
+	t.Run("failure joins close errors", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() *testFailCloser { return &testFailCloser{} })
+		mustRegister(t, c, func(*testFailCloser) (*testConfig, error) {
+			return nil, errors.New("connection failed")
+		})
+
+		err := c.Build()
+		if err == nil || !strings.Contains(err.Error(), "connection failed") || !strings.Contains(err.Error(), "close failed") {
+			t.Fatalf("expected both errors, got: %v", err)
+		}
+	})
+
+	t.Run("failed invoke closes constructed singletons", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() *testClosable { return &testClosable{Name: "db"} })
+		var db *testClosable
+		_ = c.Invoke(func(d *testClosable) error {
+			db = d
+			return errors.New("boom")
+		})
+
+		if err := c.Build(); err == nil || !strings.Contains(err.Error(), "boom") {
+			t.Fatalf("expected 'boom' in error, got: %v", err)
+		}
+		if db == nil || !db.Closed {
+			t.Fatal("singleton should be closed after the invoke failed")
+		}
+	})
+
+	t.Run("can be retried after failure", func(t *testing.T) {
+		c := New()
+		fail := true
+		var built []*testClosable
+		mustRegister(t, c, func() *testClosable {
+			db := &testClosable{Name: "db"}
+			built = append(built, db)
+			return db
+		})
+		tracers := 0
+		mustRegister(t, c, func() *testTracer {
+			tracers++
+			return &testTracer{}
+		}, WithLazy())
+		mustRegister(t, c, func(*testClosable, *testTracer) (*testConfig, error) {
+			if fail {
+				return nil, errors.New("connection failed")
+			}
+			return &testConfig{}, nil
+		})
+
+		if err := c.Build(); err == nil {
+			t.Fatal("expected error")
+		}
+		fail = false
+		mustBuild(t, c)
+
+		db, _ := Resolve[*testClosable](c)
+		if len(built) != 2 || !built[0].Closed || db != built[1] || db.Closed {
+			t.Fatalf("retry should construct a fresh instance, got %d builds", len(built))
+		}
+		if tracers != 2 {
+			t.Fatalf("retry should rebuild lazy singletons, got %d builds", tracers)
+		}
+	})
+
 	t.Run("validates named provider dependencies", func(t *testing.T) {
 		c := New()
 		mustRegisterNamed(t, c, "order", newTestOrderService)
@@ -260,6 +468,45 @@ func TestBuild(t *testing.T) {
 		}
 	})
 
+	t.Run("singleton depending on scoped returns ErrLifetimeMismatch", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger, WithLifetime(Scoped))
+		mustRegister(t, c, newTestOrderService)
+
+		err := c.Build()
+		if !errors.Is(err, ErrLifetimeMismatch) {
+			t.Fatalf("expected ErrLifetimeMismatch, got: %v", err)
+		}
+	})
+
+	t.Run("singleton depending on scoped through transient is rejected", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger, WithLifetime(Scoped))
+		mustRegister(t, c, newTestOrderService, WithLifetime(Transient))
+		mustRegister(t, c, func(o *testOrderService) *testUserService {
+			return &testUserService{Logger: o.Logger}
+		})
+
+		err := c.Build()
+		if !errors.Is(err, ErrLifetimeMismatch) {
+			t.Fatalf("expected ErrLifetimeMismatch, got: %v", err)
+		}
+	})
+
+	t.Run("scoped provider validated but not instantiated", func(t *testing.T) {
+		callCount := 0
+		c := New()
+		mustRegister(t, c, func() *testLogger {
+			callCount++
+			return &testLogger{}
+		}, WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		if callCount != 0 {
+			t.Fatalf("scoped should not be constructed during build, called %d times", callCount)
+		}
+	})
+
 	t.Run("singleton is eagerly instantiated", func(t *testing.T) {
 		callCount := 0
 		c := New()
@@ -273,6 +520,26 @@ func TestBuild(t *testing.T) {
 			t.Fatalf("singleton should be constructed once during build, called %d times", callCount)
 		}
 	})
+
+	t.Run("constructs in registration order with dependencies first", func(t *testing.T) {
+		for run := 0; run < 20; run++ {
+			c := New()
+			var order []string
+			record := func(name string) { order = append(order, name) }
+
+			mustRegister(t, c, func(*testDatabase) *testUserRepo { record("repo"); return &testUserRepo{} })
+			mustRegister(t, c, func() *testTracer { record("tracer"); return &testTracer{} })
+			mustRegister(t, c, func(*testConfig, *testLogger) *testDatabase { record("db"); return &testDatabase{} })
+			mustRegister(t, c, func() *testLogger { record("logger"); return &testLogger{} })
+			mustRegister(t, c, func() *testConfig { record("config"); return &testConfig{} })
+			mustRegister(t, c, func() *testRenderer { record("renderer"); return &testRenderer{} })
+			mustBuild(t, c)
+
+			if got := strings.Join(order, ","); got != "config,logger,db,repo,tracer,renderer" {
+				t.Fatalf("run %d: unexpected order %s", run, got)
+			}
+		}
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -334,6 +601,58 @@ func TestShutdown(t *testing.T) {
 		}
 	})
 
+	t.Run("independent singletons in reverse registration order", func(t *testing.T) {
+		type closerA struct{ testClosable }
+		type closerB struct{ testClosable }
+		type closerC struct{ testClosable }
+		type closerD struct{ testClosable }
+
+		for run := 0; run < 20; run++ {
+			c := New()
+			var order []string
+			closable := func(name string) testClosable {
+				return testClosable{Name: name, Order: &order}
+			}
+			mustRegister(t, c, func() *closerA { return &closerA{closable("a")} })
+			mustRegister(t, c, func() *closerB { return &closerB{closable("b")} })
+			mustRegister(t, c, func() *closerC { return &closerC{closable("c")} })
+			mustRegister(t, c, func() *closerD { return &closerD{closable("d")} })
+			mustBuild(t, c)
+
+			if err := c.Shutdown(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.Join(order, ","); got != "d,c,b,a" {
+				t.Fatalf("run %d: expected d,c,b,a, got %s", run, got)
+			}
+		}
+	})
+
+	t.Run("parallel build closes in the same order", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegister(t, c, func(*testLogger) *testClosable {
+			return &testClosable{Name: "service", Order: &order}
+		})
+		mustRegister(t, c, func() *testLogger { return &testLogger{} })
+		mustRegister(t, c, func() *testUnitOfWork {
+			return &testUnitOfWork{Name: "uow", Order: &order}
+		})
+		mustRegister(t, c, func() *testRequestHandler {
+			return &testRequestHandler{Order: &order}
+		})
+		if err := c.Build(WithParallelBuild(4)); err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := strings.Join(order, ","); got != "handler,uow,service" {
+			t.Fatalf("expected handler,uow,service, got %s", got)
+		}
+	})
+
 	t.Run("skips non-closer singletons", func(t *testing.T) {
 		c := New()
 		mustRegister(t, c, newTestLogger)
@@ -362,6 +681,55 @@ func TestShutdown(t *testing.T) {
 		}
 	})
 
+	t.Run("passes the context to Shutdowner instead of closing", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() *testGracefulServer { return &testGracefulServer{} })
+		mustBuild(t, c)
+
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "v")
+		if err := c.Shutdown(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		srv, _ := Resolve[*testGracefulServer](c)
+		if srv.Ctx == nil || srv.Ctx.Value(key{}) != "v" {
+			t.Fatal("Shutdown should receive the context")
+		}
+		if srv.Closed {
+			t.Fatal("Close should not be called on a Shutdowner")
+		}
+	})
+
+	t.Run("deadline names the timed-out and skipped instances", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegister(t, c, func() *testClosable {
+			return &testClosable{Name: "db", Order: &order}
+		})
+		mustRegister(t, c, func(*testClosable) *testGracefulServer {
+			return &testGracefulServer{Block: true}
+		})
+		mustBuild(t, c)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := c.Shutdown(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got: %v", err)
+		}
+		for _, want := range []string{
+			"closing *oak.testGracefulServer: context deadline exceeded",
+			"closing *oak.testClosable: skipped: context deadline exceeded",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Fatalf("expected %q in error, got: %v", want, err)
+			}
+		}
+		if len(order) != 0 {
+			t.Fatalf("skipped closer should not run, got %v", order)
+		}
+	})
+
 	t.Run("respects context cancellation", func(t *testing.T) {
 		c := New()
 		var order []string
@@ -422,3 +790,154 @@ func TestShutdown(t *testing.T) {
 		}
 	})
 }
+
+// ---------------------------------------------------------------------------
+// Cleanup functions
+// ---------------------------------------------------------------------------
+
+func TestConstructorCleanup(t *testing.T) {
+	t.Run("runs in reverse dependency order on Shutdown", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegister(t, c, func() (*testConfig, func(), error) {
+			return &testConfig{}, func() { order = append(order, "config") }, nil
+		})
+		mustRegister(t, c, func(*testConfig) (*testLogger, func() error, error) {
+			return &testLogger{}, func() error {
+				order = append(order, "logger")
+				return nil
+			}, nil
+		})
+		mustBuild(t, c)
+
+		if len(order) != 0 {
+			t.Fatalf("cleanups should not run during Build, got %v", order)
+		}
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+		if got := strings.Join(order, ","); got != "logger,config" {
+			t.Fatalf("expected [logger config], got %v", order)
+		}
+	})
+
+	t.Run("runs after Close and WithCleanup", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegister(t, c, func() (*testClosable, func(), error) {
+			return &testClosable{Name: "close", Order: &order}, func() { order = append(order, "cleanup") }, nil
+		}, WithCleanup(func(*testClosable) error {
+			order = append(order, "option")
+			return nil
+		}))
+		mustBuild(t, c)
+
+		_ = c.Shutdown(context.Background())
+		if got := strings.Join(order, ","); got != "option,close,cleanup" {
+			t.Fatalf("expected [option close cleanup], got %v", order)
+		}
+	})
+
+	t.Run("errors are joined with the type", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() (*testLogger, func() error, error) {
+			return &testLogger{}, func() error { return errors.New("flush failed") }, nil
+		})
+		mustBuild(t, c)
+
+		err := c.Shutdown(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "closing *oak.testLogger: flush failed") {
+			t.Fatalf("expected cleanup error, got: %v", err)
+		}
+	})
+
+	t.Run("runs when a later step of Build fails", func(t *testing.T) {
+		c := New()
+		cleaned := false
+		mustRegister(t, c, func() (*testLogger, func(), error) {
+			return &testLogger{}, func() { cleaned = true }, nil
+		})
+		mustRegister(t, c, func(*testLogger) (*testConfig, error) {
+			return nil, errors.New("boom")
+		})
+
+		if err := c.Build(); err == nil {
+			t.Fatal("expected error")
+		}
+		if !cleaned {
+			t.Fatal("cleanup should run when Build fails")
+		}
+	})
+
+	t.Run("runs when a decorator fails", func(t *testing.T) {
+		c := New()
+		cleaned := false
+		mustRegister(t, c, func() (*testLogger, func(), error) {
+			return &testLogger{}, func() { cleaned = true }, nil
+		})
+		if err := c.Decorate(func(*testLogger) (*testLogger, error) {
+			return nil, errors.New("boom")
+		}); err != nil {
+			t.Fatalf("Decorate: %v", err)
+		}
+
+		if err := c.Build(); err == nil {
+			t.Fatal("expected error")
+		}
+		if !cleaned {
+			t.Fatal("cleanup should run when decorating fails")
+		}
+	})
+
+	t.Run("is not tracked when the constructor fails", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() (*testLogger, func(), error) {
+			return nil, func() { t.Error("cleanup should not run") }, errors.New("boom")
+		})
+
+		if err := c.Build(); err == nil || !strings.Contains(err.Error(), "boom") {
+			t.Fatalf("expected constructor error, got: %v", err)
+		}
+	})
+
+	t.Run("scoped cleanup runs on scope Close", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegister(t, c, func() (*testUnitOfWork, func(), error) {
+			return &testUnitOfWork{Name: "uow", Order: &order}, func() { order = append(order, "cleanup") }, nil
+		}, WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		_, _ = Resolve[*testUnitOfWork](s)
+		if err := s.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		if got := strings.Join(order, ","); got != "uow,cleanup" {
+			t.Fatalf("expected [uow cleanup], got %v", order)
+		}
+	})
+
+	t.Run("rejected for transient and named providers", func(t *testing.T) {
+		ctor := func() (*testLogger, func(), error) { return &testLogger{}, func() {}, nil }
+		if err := New().Register(ctor, WithLifetime(Transient)); err == nil {
+			t.Fatal("expected error for transient")
+		}
+		if err := New().RegisterNamed("log", ctor); err == nil {
+			t.Fatal("expected error for named provider")
+		}
+	})
+
+	t.Run("rejects invalid signatures", func(t *testing.T) {
+		for _, ctor := range []interface{}{
+			func() (*testLogger, func(int), error) { return nil, nil, nil },
+			func() (*testLogger, func() int, error) { return nil, nil, nil },
+			func() (*testLogger, func(), int) { return nil, nil, 0 },
+			func() (*testLogger, func(), error, error) { return nil, nil, nil, nil },
+		} {
+			if err := New().Register(ctor); err == nil {
+				t.Fatalf("expected error for %T", ctor)
+			}
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package oak
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// decorator wraps the instances of a registered type. It is created by
+// [Container.Decorate].
+type decorator struct {
+	fn reflect.Value
+
+	// self is the index of the parameter that receives the current value.
+	self int
+
+	// deps describes every other parameter of fn.
+	deps []dependency
+}
+
+func (c *container) Decorate(fn interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.built {
+		return ErrAlreadyBuilt
+	}
+
+	val := reflect.ValueOf(fn)
+	if !val.IsValid() || val.Kind() != reflect.Func {
+		return errors.New("decorator must be a function")
+	}
+	typ := val.Type()
+
+	if err := checkResults("decorator", typ); err != nil {
+		return err
+	}
+
+	t := typ.Out(0)
+	self := -1
+	for i := 0; i < typ.NumIn(); i++ {
+		if typ.In(i) != t {
+			continue
+		}
+		if self >= 0 {
+			return fmt.Errorf("decorator for %s takes more than one %s parameter", t, t)
+		}
+		self = i
+	}
+	if self < 0 {
+		return fmt.Errorf("decorator for %s must take a %s parameter", t, t)
+	}
+
+	deps, err := newDependencies(typ, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	d := &decorator{fn: val, self: self}
+	for _, dep := range deps {
+		if dep.index != self {
+			d.deps = append(d.deps, dep)
+		}
+	}
+
+	if len(c.decorators[t]) == 0 {
+		c.decoratedTypes = append(c.decoratedTypes, t)
+	}
+	c.decorators[t] = append(c.decorators[t], d)
+	return nil
+}
+
+// decorate applies the decorators registered for t to inst, in registration
+// order.
+func (c *container) decorate(t reflect.Type, inst reflect.Value, s *scope) (reflect.Value, error) {
+	for _, d := range c.decorators[t] {
+		args, err := c.arguments(d.fn.Type(), d.deps, s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("decorating %s: %w", t, err)
+		}
+		args[d.self] = inst
+
+		inst, _, err = call(d.fn, args)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("decorating %s: %w", t, err)
+		}
+	}
+	return inst, nil
+}
+
+// decorateBinding applies the decorators registered for t, an interface type
+// p is bound to with [As], on top of p's instance inst. The result is cached
+// with p's lifetime: in the container for singletons and in s for scoped
+// providers.
+func (c *container) decorateBinding(t reflect.Type, p *provider, inst reflect.Value, s *scope) (reflect.Value, error) {
+	switch p.lifetime {
+	case Singleton:
+		if p.lazy != nil {
+			// Decorated along with the instance by lazyInstance.
+			return p.lazy.bindings[t], nil
+		}
+		if v, ok := c.decorated[t]; ok {
+			return v, nil
+		}
+	case Scoped:
+		if v, ok := s.decorated[t]; ok {
+			return v, nil
+		}
+	}
+
+	v, err := c.decorate(t, inst, s)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	switch p.lifetime {
+	case Singleton:
+		c.decorated[t] = v
+	case Scoped:
+		s.decorated[t] = v
+	}
+	return v, nil
+}
+
+// edges returns the dependencies of p followed by those of every decorator
+// applied to p's types.
+func (c *container) edges(p *provider) []dependency {
+	if p.name != "" || p.group != "" {
+		return p.deps
+	}
+
+	deps := p.deps
+	for _, t := range p.keys() {
+		for _, d := range c.decorators[t] {
+			deps = append(deps[:len(deps):len(deps)], d.deps...)
+		}
+	}
+	return deps
+}
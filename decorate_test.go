@@ -0,0 +1,217 @@
+package oak
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testMeteredService decorates a testService.
+type testMeteredService struct {
+	Inner  testService
+	Logger *testLogger
+}
+
+func (s *testMeteredService) Name() string { return "metered-" + s.Inner.Name() }
+
+func TestDecorate(t *testing.T) {
+	t.Run("consumers receive the decorated value", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestDatabase)
+		mustRegister(t, c, newTestConfig)
+		if err := c.Decorate(func(cfg *testConfig) *testConfig {
+			return &testConfig{DSN: cfg.DSN + "?sslmode=disable"}
+		}); err != nil {
+			t.Fatalf("Decorate: %v", err)
+		}
+		mustBuild(t, c)
+
+		cfg, _ := Resolve[*testConfig](c)
+		db, _ := Resolve[*testDatabase](c)
+		if cfg.DSN != "postgres://localhost?sslmode=disable" {
+			t.Fatalf("unexpected DSN: %s", cfg.DSN)
+		}
+		if db.Config != cfg {
+			t.Fatal("consumer should receive the decorated singleton")
+		}
+	})
+
+	t.Run("decorators stack in registration order", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() *testLogger { return &testLogger{Prefix: "app"} })
+		_ = c.Decorate(func(l *testLogger) *testLogger { return &testLogger{Prefix: l.Prefix + "-a"} })
+		_ = c.Decorate(func(l *testLogger) (*testLogger, error) { return &testLogger{Prefix: l.Prefix + "-b"}, nil })
+		mustBuild(t, c)
+
+		l, _ := Resolve[*testLogger](c)
+		if l.Prefix != "app-a-b" {
+			t.Fatalf("expected app-a-b, got %s", l.Prefix)
+		}
+	})
+
+	t.Run("decorator receives other dependencies", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func() testService { return &testOrderService{} })
+		_ = c.Decorate(func(log *testLogger, s testService) testService {
+			return &testMeteredService{Inner: s, Logger: log}
+		})
+		mustBuild(t, c)
+
+		svc, _ := Resolve[testService](c)
+		m, ok := svc.(*testMeteredService)
+		if !ok || m.Logger == nil || svc.Name() != "metered-order" {
+			t.Fatalf("unexpected service: %#v", svc)
+		}
+	})
+
+	t.Run("singleton decorated once", func(t *testing.T) {
+		calls := 0
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		_ = c.Decorate(func(l *testLogger) *testLogger {
+			calls++
+			return l
+		})
+		mustRegister(t, c, newTestOrderService)
+		mustBuild(t, c)
+
+		_, _ = Resolve[*testLogger](c)
+		_, _ = Resolve[*testLogger](c)
+		if calls != 1 {
+			t.Fatalf("expected 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("transient decorated on every construction", func(t *testing.T) {
+		calls := 0
+		c := New()
+		mustRegister(t, c, newTestLogger, WithLifetime(Transient))
+		_ = c.Decorate(func(l *testLogger) *testLogger {
+			calls++
+			return l
+		})
+		mustRegister(t, c, newTestOrderService, WithLifetime(Transient))
+		mustBuild(t, c)
+
+		_, _ = Resolve[*testLogger](c)
+		_, _ = Resolve[*testOrderService](c)
+		if calls != 2 {
+			t.Fatalf("expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("interface binding is decorated", func(t *testing.T) {
+		type consumer struct{ Svc testService }
+
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, As[testService]())
+		_ = c.Decorate(func(s testService) testService { return &testMeteredService{Inner: s} })
+		mustRegister(t, c, func(s testService) *consumer { return &consumer{Svc: s} })
+		mustBuild(t, c)
+
+		svc, _ := Resolve[testService](c)
+		cons, _ := Resolve[*consumer](c)
+		concrete, _ := Resolve[*testOrderService](c)
+
+		if svc.Name() != "metered-order" || cons.Svc != svc {
+			t.Fatalf("binding should be decorated once and shared, got %s", svc.Name())
+		}
+		if svc.(*testMeteredService).Inner != testService(concrete) {
+			t.Fatal("decorator should wrap the concrete singleton")
+		}
+	})
+
+	t.Run("scoped binding decorated once per scope", func(t *testing.T) {
+		calls := 0
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, As[testService](), WithLifetime(Scoped))
+		_ = c.Decorate(func(s testService) testService {
+			calls++
+			return &testMeteredService{Inner: s}
+		})
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		a, _ := Resolve[testService](s)
+		b, _ := Resolve[testService](s)
+		if a != b || calls != 1 {
+			t.Fatalf("expected one shared decoration, got %d calls", calls)
+		}
+	})
+
+	t.Run("decorator error propagates", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		_ = c.Decorate(func(l *testLogger) (*testLogger, error) {
+			return nil, errors.New("decoration failed")
+		})
+
+		err := c.Build()
+		if err == nil || !strings.Contains(err.Error(), "decoration failed") {
+			t.Fatalf("expected decoration error, got: %v", err)
+		}
+	})
+
+	t.Run("undecorated type returns ErrProviderNotFound", func(t *testing.T) {
+		c := New()
+		_ = c.Decorate(func(l *testLogger) *testLogger { return l })
+
+		if err := c.Build(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("missing decorator dependency returns ErrProviderNotFound", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		_ = c.Decorate(func(l *testLogger, cfg *testConfig) *testLogger { return l })
+
+		if err := c.Build(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("cycle through decorator is detected", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService)
+		_ = c.Decorate(func(l *testLogger, o *testOrderService) *testLogger { return l })
+
+		if err := c.Build(); !errors.Is(err, ErrCircularDependency) {
+			t.Fatalf("expected ErrCircularDependency, got: %v", err)
+		}
+	})
+
+	t.Run("invalid decorators are rejected", func(t *testing.T) {
+		c := New()
+		if err := c.Decorate("not a function"); err == nil {
+			t.Error("non-function: expected error")
+		}
+		if err := c.Decorate(nil); err == nil || err.Error() != "decorator must be a function" {
+			t.Errorf("nil: expected error, got: %v", err)
+		}
+		if err := c.Decorate(func(l *testLogger) {}); err == nil {
+			t.Error("no result: expected error")
+		}
+		if err := c.Decorate(func() *testLogger { return nil }); err == nil {
+			t.Error("missing decorated parameter: expected error")
+		}
+		if err := c.Decorate(func(a, b *testLogger) *testLogger { return a }); err == nil {
+			t.Error("two decorated parameters: expected error")
+		}
+	})
+
+	t.Run("after build returns ErrAlreadyBuilt", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustBuild(t, c)
+
+		if err := c.Decorate(func(l *testLogger) *testLogger { return l }); !errors.Is(err, ErrAlreadyBuilt) {
+			t.Fatalf("expected ErrAlreadyBuilt, got: %v", err)
+		}
+	})
+}
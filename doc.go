@@ -22,8 +22,39 @@
 //
 // [Transient] — a fresh instance on every [Container.Resolve] call.
 //
+// [Scoped] — one instance per [Scope], created with [Container.NewScope].
+//
 //	c.Register(NewLogger, oak.WithLifetime(oak.Transient))
 //
+// [WithLazy] defers a singleton's construction from [Container.Build] to its
+// first resolution, and [WithParallelBuild] lets Build construct independent
+// singletons concurrently.
+//
+// # Scopes
+//
+// A [Scope] is a short-lived child of the container, typically one per
+// request. Scoped instances are shared within the scope and closed with it:
+//
+//	scope := c.NewScope()
+//	defer scope.Close()
+//	uow, err := oak.Resolve[*UnitOfWork](scope)
+//
+// # Interface Bindings
+//
+// Use [As] to let a concrete constructor satisfy an interface dependency.
+// All bindings share the same instance:
+//
+//	c.Register(NewPostgresUserRepo, oak.As[UserRepository]())
+//
+// # Value Groups
+//
+// [InGroup] adds a provider to a named group; [WithParamGroup] injects every
+// member of a group into a slice parameter:
+//
+//	c.Register(NewUsersHandler, oak.InGroup("routes"))
+//	c.Register(NewOrdersHandler, oak.InGroup("routes"))
+//	c.Register(NewRouter, oak.WithParamGroup(0, "routes"))
+//
 // # Named Providers
 //
 // When you need several implementations of the same return type, use named
@@ -34,15 +65,135 @@
 //
 //	db, _ := oak.ResolveNamed[Database](c, "postgres")
 //
+// Named providers are [Transient] unless registered with [WithLifetime]; a
+// named [Singleton] is built by Build, cached per name and closed by
+// [Container.Shutdown]:
+//
+//	c.RegisterNamed("primary", NewPrimaryDB, oak.WithLifetime(oak.Singleton))
+//
+// [WithParamName] injects a named provider into a constructor parameter:
+//
+//	c.Register(NewReportService, oak.WithParamName(0, "postgres"))
+//
+// # Parameter Structs
+//
+// A constructor parameter whose struct type embeds [In] has each exported
+// field injected individually. The oak struct tag selects named providers,
+// value groups and optional dependencies:
+//
+//	type ServerParams struct {
+//	    oak.In
+//
+//	    DB     *sql.DB        `oak:"name=primary"`
+//	    Tracer *Tracer        `oak:"optional"`
+//	    Routes []http.Handler `oak:"group=routes"`
+//	}
+//
+// # Optional Dependencies
+//
+// A parameter of type [Optional] receives the provider's instance when one is
+// registered and the zero value otherwise, without failing [Container.Build]:
+//
+//	func NewServer(tracer oak.Optional[*Tracer]) *Server
+//
+// # Lazy Dependencies
+//
+// A parameter of type [Lazy] defers resolution until [Lazy.Get] is called.
+// Build does not follow lazy edges when detecting cycles:
+//
+//	func NewReportService(pdf oak.Lazy[*PDFRenderer]) *ReportService
+//
+// # Factory Injection
+//
+// A parameter of type [Provider], or a plain func() (T, error), receives a
+// factory that resolves T on every call:
+//
+//	func NewHandler(newReq oak.Provider[*RequestBuilder]) *Handler
+//
+// # Result Structs
+//
+// A constructor returning a struct that embeds [Out] registers every exported
+// field as its own provider. The constructor runs once per lifetime:
+//
+//	type DBResult struct {
+//	    oak.Out
+//
+//	    DB      *sql.DB
+//	    Replica *sql.DB `oak:"name=replica"`
+//	}
+//
+// # Decorators
+//
+// [Container.Decorate] wraps an already-registered type. Every consumer
+// receives the decorated value, and decorators stack in registration order:
+//
+//	c.Decorate(func(repo UserRepository, m *Metrics) UserRepository {
+//	    return &meteredRepo{next: repo, metrics: m}
+//	})
+//
+// # Invoke
+//
+// [Container.Invoke] calls a function with injected arguments. Called after
+// [Container.Build], the function may block until [Container.Shutdown]:
+//
+//	c.Invoke(func(srv *http.Server) error {
+//	    return srv.ListenAndServe()
+//	})
+//
+// Called before Build, the function runs at the end of Build instead, while
+// Build holds the container's lock, so it must not block.
+//
+// The generic [Invoke] returns the function's result from a built container.
+//
+// # Dependency Graph
+//
+// [Container.Graph] returns the providers and their dependencies, which can
+// be rendered with [Graph.DOT], [Graph.Mermaid] or [Graph.JSON]:
+//
+//	fmt.Print(c.Graph().Mermaid())
+//
+// [Container.Providers] describes every registered provider, and [Has] and
+// [HasNamed] check for a single one.
+//
+// # Lifecycle
+//
+// [Container.Start] starts the singletons in dependency order: those
+// implementing [Starter] have Start called, then the hooks registered with
+// [OnStart] run. [Container.Shutdown] stops them in reverse through [OnStop]
+// hooks and [Stopper] before closing anything:
+//
+//	c.Register(NewServer, oak.OnStop(func(ctx context.Context, s *Server) error {
+//	    return s.Drain(ctx)
+//	}))
+//
+//	if err := c.Start(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//
 // # Graceful Shutdown
 //
 // Singleton providers that implement [io.Closer] are automatically tracked
 // during [Container.Build]. Call [Container.Shutdown] to close them in reverse
-// dependency order:
+// dependency order. Singletons implementing [Shutdowner], such as
+// [net/http.Server], are passed the context instead, and once it expires the
+// remaining ones are skipped with an error naming their type:
 //
 //	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 //	defer cancel()
 //	if err := c.Shutdown(ctx); err != nil {
 //	    log.Println("shutdown error:", err)
 //	}
+//
+// [WithCleanup] registers a cleanup function for types that cannot implement
+// io.Closer; it runs in the same order:
+//
+//	c.Register(NewRedisClient, oak.WithCleanup(func(r *redis.Client) error {
+//	    return r.Quit()
+//	}))
+//
+// A constructor may also return a cleanup function as its second result, as
+// (T, func(), error) or (T, func() error, error). It runs with the closers,
+// and right away if Build fails:
+//
+//	func NewDB(cfg *Config) (*sql.DB, func(), error)
 package oak
@@ -1,6 +1,11 @@
 package oak
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
 
 var (
 	// ErrNotBuilt is returned when Resolve is called before Build.
@@ -11,11 +16,12 @@ var (
 	ErrAlreadyBuilt = errors.New("container already built")
 
 	// ErrProviderNotFound is returned when no provider is registered for the
-	// requested type or name.
+	// requested type or name. [MissingProviderError] has the details.
 	ErrProviderNotFound = errors.New("provider not found")
 
 	// ErrCircularDependency is returned when the dependency graph contains a
-	// cycle. The error message includes the full chain.
+	// cycle. The error message includes the full chain, and [CycleError]
+	// holds it as types.
 	ErrCircularDependency = errors.New("circular dependency detected")
 
 	// ErrDuplicateProvider is returned when a provider for the same type or
@@ -25,4 +31,126 @@ var (
 	// ErrAlreadyShutdown is returned when [Container.Shutdown] is called
 	// more than once.
 	ErrAlreadyShutdown = errors.New("container already shut down")
+
+	// ErrScopeRequired is returned when a [Scoped] provider is resolved
+	// directly from the container instead of from a [Scope].
+	ErrScopeRequired = errors.New("scoped provider requires a scope")
+
+	// ErrLifetimeMismatch is returned by [Container.Build] when a [Singleton]
+	// provider depends on a [Scoped] provider, directly or through a
+	// [Transient] one.
+	ErrLifetimeMismatch = errors.New("singleton depends on scoped provider")
+
+	// ErrAlreadyStarted is returned when [Container.Start] is called after
+	// it has already succeeded.
+	ErrAlreadyStarted = errors.New("container already started")
+
+	// ErrScopeClosed is returned when a [Scope] is used after it has been
+	// closed.
+	ErrScopeClosed = errors.New("scope already closed")
 )
+
+// CycleError is returned by [Container.Build] when the dependency graph
+// contains a cycle. It matches [ErrCircularDependency] with [errors.Is]:
+//
+//	var cycle *oak.CycleError
+//	if errors.As(err, &cycle) {
+//	    fmt.Println(cycle.Path)
+//	}
+type CycleError struct {
+	// Path lists the types Build followed, from the provider it started at
+	// to the type that closes the cycle. That last type also appears
+	// earlier in Path, where the cycle begins.
+	Path []reflect.Type
+}
+
+func (e *CycleError) Error() string {
+	chain := make([]string, len(e.Path))
+	for i, t := range e.Path {
+		chain[i] = t.String()
+	}
+	return fmt.Sprintf("%s: %s", ErrCircularDependency, strings.Join(chain, " -> "))
+}
+
+// Is reports whether target is [ErrCircularDependency].
+func (e *CycleError) Is(target error) bool {
+	return target == ErrCircularDependency
+}
+
+// MissingProviderError is returned when a type or name has no provider,
+// either by [Container.Build] for a dependency or when it is resolved
+// directly. It matches [ErrProviderNotFound] with [errors.Is].
+type MissingProviderError struct {
+	// Type is the requested type. For a named dependency it is the type of
+	// the parameter the named provider should fill.
+	Type reflect.Type
+
+	// Name is set when a named provider is missing.
+	Name string
+
+	// RequiredBy is the type of the provider that depends on Type, or nil
+	// when Type is resolved or invoked directly.
+	RequiredBy reflect.Type
+
+	// Path lists the dependency chain Build followed, from the provider it
+	// started at to Type itself. It is empty when RequiredBy is nil.
+	Path []reflect.Type
+
+	// site describes where RequiredBy was registered.
+	site string
+}
+
+func (e *MissingProviderError) Error() string {
+	msg := fmt.Sprintf("%s: %s", ErrProviderNotFound, e.Type)
+	if e.Name != "" {
+		msg = fmt.Sprintf("%s: named %q", ErrProviderNotFound, e.Name)
+	}
+
+	switch {
+	case e.site != "":
+		msg += fmt.Sprintf(" (required by %s)", e.site)
+	case e.RequiredBy != nil:
+		msg += fmt.Sprintf(" (required by %s)", e.RequiredBy)
+	}
+	return msg
+}
+
+// Is reports whether target is [ErrProviderNotFound].
+func (e *MissingProviderError) Is(target error) bool {
+	return target == ErrProviderNotFound
+}
+
+// ConstructorError is returned when a constructor, or one of the decorators
+// applied to its result, fails. It wraps the underlying error, which
+// [errors.Is] and [errors.As] reach through Unwrap. When a dependency fails
+// while being constructed for another provider, Err holds the dependency's
+// own ConstructorError.
+type ConstructorError struct {
+	// Type is the type the provider returns.
+	Type reflect.Type
+
+	// Name is the provider's name, for a named provider.
+	Name string
+
+	// Err is the error returned by the constructor, or by the resolution of
+	// its arguments or its decorators.
+	Err error
+
+	// site describes where the provider was registered.
+	site string
+}
+
+func (e *ConstructorError) Error() string {
+	what := e.Type.String()
+	if e.Name != "" {
+		what = fmt.Sprintf("named provider %q", e.Name)
+	}
+	if e.site != "" {
+		what += fmt.Sprintf(" (%s)", e.site)
+	}
+	return fmt.Sprintf("constructing %s: %v", what, e.Err)
+}
+
+func (e *ConstructorError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,140 @@
+package oak
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCycleError(t *testing.T) {
+	c := New()
+	mustRegister(t, c, newTestCircA)
+	mustRegister(t, c, newTestCircB)
+	mustRegister(t, c, newTestCircC)
+
+	err := c.Build()
+	var cycle *CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("expected *CycleError, got: %v", err)
+	}
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("expected ErrCircularDependency, got: %v", err)
+	}
+
+	want := []reflect.Type{
+		reflect.TypeOf(&testCircA{}),
+		reflect.TypeOf(&testCircB{}),
+		reflect.TypeOf(&testCircC{}),
+		reflect.TypeOf(&testCircA{}),
+	}
+	if !reflect.DeepEqual(cycle.Path, want) {
+		t.Fatalf("unexpected path: %v", cycle.Path)
+	}
+	if got := err.Error(); got != "circular dependency detected: *oak.testCircA -> *oak.testCircB -> *oak.testCircC -> *oak.testCircA" {
+		t.Fatalf("unexpected message: %s", got)
+	}
+}
+
+func TestMissingProviderError(t *testing.T) {
+	t.Run("build reports the dependent and path", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func(*testOrderService) *testConfig { return &testConfig{} })
+		mustRegister(t, c, newTestOrderService)
+
+		err := c.Build()
+		var missing *MissingProviderError
+		if !errors.As(err, &missing) || !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected *MissingProviderError, got: %v", err)
+		}
+
+		logger := reflect.TypeOf(&testLogger{})
+		orders := reflect.TypeOf(&testOrderService{})
+		if missing.Type != logger || missing.Name != "" || missing.RequiredBy != orders {
+			t.Fatalf("unexpected error: %+v", missing)
+		}
+		want := []reflect.Type{reflect.TypeOf(&testConfig{}), orders, logger}
+		if !reflect.DeepEqual(missing.Path, want) {
+			t.Fatalf("unexpected path: %v", missing.Path)
+		}
+	})
+
+	t.Run("named dependency", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestOrderService, WithParamName(0, "audit"))
+
+		var missing *MissingProviderError
+		if err := c.Build(); !errors.As(err, &missing) {
+			t.Fatalf("expected *MissingProviderError, got: %v", err)
+		}
+		if missing.Name != "audit" || missing.Type != reflect.TypeOf(&testLogger{}) {
+			t.Fatalf("unexpected error: %+v", missing)
+		}
+	})
+
+	t.Run("direct resolution has no dependent", func(t *testing.T) {
+		c := New()
+		mustBuild(t, c)
+
+		_, err := Resolve[*testLogger](c)
+		var missing *MissingProviderError
+		if !errors.As(err, &missing) {
+			t.Fatalf("expected *MissingProviderError, got: %v", err)
+		}
+		if missing.RequiredBy != nil || missing.Path != nil {
+			t.Fatalf("unexpected error: %+v", missing)
+		}
+		if got := err.Error(); got != "provider not found: *oak.testLogger" {
+			t.Fatalf("unexpected message: %s", got)
+		}
+	})
+
+	t.Run("message without registration site", func(t *testing.T) {
+		err := &MissingProviderError{Type: reflect.TypeOf(&testLogger{}), RequiredBy: reflect.TypeOf(&testConfig{})}
+		if got := err.Error(); got != "provider not found: *oak.testLogger (required by *oak.testConfig)" {
+			t.Fatalf("unexpected message: %s", got)
+		}
+	})
+}
+
+func TestConstructorError(t *testing.T) {
+	t.Run("wraps the constructor error", func(t *testing.T) {
+		boom := errors.New("boom")
+		c := New()
+		mustRegisterNamed(t, c, "cfg", func() (*testConfig, error) { return nil, boom }, WithLifetime(Singleton))
+
+		err := c.Build()
+		var ctor *ConstructorError
+		if !errors.As(err, &ctor) {
+			t.Fatalf("expected *ConstructorError, got: %v", err)
+		}
+		if ctor.Type != reflect.TypeOf(&testConfig{}) || ctor.Name != "cfg" || ctor.Err != boom {
+			t.Fatalf("unexpected error: %+v", ctor)
+		}
+		if !errors.Is(err, boom) {
+			t.Fatalf("expected the constructor error to be reachable, got: %v", err)
+		}
+	})
+
+	t.Run("nests dependency failures", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() (*testLogger, error) { return nil, errors.New("boom") }, WithLifetime(Transient))
+		mustRegister(t, c, newTestOrderService)
+
+		err := c.Build()
+		var outer *ConstructorError
+		if !errors.As(err, &outer) || outer.Type != reflect.TypeOf(&testOrderService{}) {
+			t.Fatalf("expected the dependent's error, got: %v", err)
+		}
+		var inner *ConstructorError
+		if !errors.As(outer.Err, &inner) || inner.Type != reflect.TypeOf(&testLogger{}) {
+			t.Fatalf("expected the dependency's error, got: %v", outer.Err)
+		}
+	})
+
+	t.Run("message without registration site", func(t *testing.T) {
+		err := &ConstructorError{Type: reflect.TypeOf(&testConfig{}), Err: errors.New("boom")}
+		if got := err.Error(); got != "constructing *oak.testConfig: boom" {
+			t.Fatalf("unexpected message: %s", got)
+		}
+	})
+}
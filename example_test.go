@@ -2,6 +2,7 @@ package oak_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ARTM2000/oak"
@@ -53,6 +54,71 @@ func ExampleWithLifetime() {
 	// Output: false
 }
 
+func ExampleWithLazy() {
+	c := oak.New()
+	_ = c.Register(func() *Config {
+		fmt.Println("loading config")
+		return &Config{DSN: "postgres://localhost"}
+	}, oak.WithLazy())
+	_ = c.Build()
+	fmt.Println("built")
+
+	cfg, _ := oak.Resolve[*Config](c)
+	_, _ = oak.Resolve[*Config](c)
+	fmt.Println(cfg.DSN)
+	// Output:
+	// built
+	// loading config
+	// postgres://localhost
+}
+
+func ExampleWithCleanup() {
+	c := oak.New()
+	_ = c.Register(func() *Config {
+		return &Config{DSN: "postgres://localhost"}
+	}, oak.WithCleanup(func(cfg *Config) error {
+		fmt.Println("releasing", cfg.DSN)
+		return nil
+	}))
+	_ = c.Build()
+
+	_ = c.Shutdown(context.Background())
+	// Output:
+	// releasing postgres://localhost
+}
+
+func ExampleContainer_Register_cleanup() {
+	c := oak.New()
+	_ = c.Register(func() (*Config, func(), error) {
+		cfg := &Config{DSN: "postgres://localhost"}
+		return cfg, func() { fmt.Println("cleaning up", cfg.DSN) }, nil
+	})
+	_ = c.Build()
+
+	_ = c.Shutdown(context.Background())
+	// Output:
+	// cleaning up postgres://localhost
+}
+
+func ExampleWithParallelBuild() {
+	c := oak.New()
+	_ = c.Register(func() *Config { return &Config{DSN: "postgres://localhost"} })
+	_ = c.Register(func() *Logger { return &Logger{Prefix: "app"} })
+	_ = c.Register(func(cfg *Config, log *Logger) *Database {
+		return &Database{Config: cfg, Logger: log}
+	})
+
+	// Config and Logger are built concurrently, then Database.
+	if err := c.Build(oak.WithParallelBuild(2)); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	db, _ := oak.Resolve[*Database](c)
+	fmt.Println(db.Config.DSN, db.Logger.Prefix)
+	// Output: postgres://localhost app
+}
+
 func ExampleResolve() {
 	c := oak.New()
 	_ = c.Register(func() *Config { return &Config{DSN: "postgres://localhost"} })
@@ -73,6 +139,168 @@ func ExampleResolve() {
 	// app
 }
 
+func ExampleAs() {
+	c := oak.New()
+	_ = c.Register(func() *englishGreeter { return &englishGreeter{} }, oak.As[Greeter]())
+	_ = c.Build()
+
+	g, _ := oak.Resolve[Greeter](c)
+	fmt.Println(g.Greet())
+	// Output: hello
+}
+
+func ExampleInGroup() {
+	type greeters struct{ All []Greeter }
+
+	c := oak.New()
+	_ = c.Register(func() Greeter { return &englishGreeter{} }, oak.InGroup("greeters"))
+	_ = c.Register(func() Greeter { return &spanishGreeter{} }, oak.InGroup("greeters"))
+	_ = c.Register(func(all []Greeter) *greeters {
+		return &greeters{All: all}
+	}, oak.WithParamGroup(0, "greeters"))
+	_ = c.Build()
+
+	g, _ := oak.Resolve[*greeters](c)
+	for _, greeter := range g.All {
+		fmt.Println(greeter.Greet())
+	}
+	// Output:
+	// hello
+	// hola
+}
+
+func ExampleIn() {
+	type params struct {
+		oak.In
+
+		Config *Config
+		Logger *Logger `oak:"name=audit"`
+		Greet  Greeter `oak:"optional"`
+	}
+
+	c := oak.New()
+	_ = c.Register(func() *Config { return &Config{DSN: "postgres://localhost"} })
+	_ = c.RegisterNamed("audit", func() *Logger { return &Logger{Prefix: "audit"} })
+	_ = c.Register(func(p params) *Database {
+		fmt.Println("greeter present:", p.Greet != nil)
+		return &Database{Config: p.Config, Logger: p.Logger}
+	})
+	_ = c.Build()
+
+	db, _ := oak.Resolve[*Database](c)
+	fmt.Println(db.Logger.Prefix)
+	// Output:
+	// greeter present: false
+	// audit
+}
+
+func ExampleOptional() {
+	c := oak.New()
+	_ = c.Register(func() *Config { return &Config{DSN: "postgres://localhost"} })
+	_ = c.Register(func(cfg oak.Optional[*Config], log oak.Optional[*Logger]) *Database {
+		fmt.Println("config present:", cfg.Present)
+		fmt.Println("logger present:", log.Present)
+		return &Database{Config: cfg.Value, Logger: log.Value}
+	})
+	_ = c.Build()
+	// Output:
+	// config present: true
+	// logger present: false
+}
+
+// reportService needs its config only when a report is exported.
+type reportService struct {
+	cfg oak.Lazy[*Config]
+}
+
+func ExampleLazy() {
+	c := oak.New()
+	_ = c.Register(func() *Config {
+		fmt.Println("loading config")
+		return &Config{DSN: "postgres://localhost"}
+	}, oak.WithLifetime(oak.Transient))
+	_ = c.Register(func(cfg oak.Lazy[*Config]) *reportService {
+		return &reportService{cfg: cfg}
+	})
+	_ = c.Build()
+
+	svc, _ := oak.Resolve[*reportService](c)
+	fmt.Println("service ready")
+
+	cfg, _ := svc.cfg.Get()
+	fmt.Println(cfg.DSN)
+	// Output:
+	// service ready
+	// loading config
+	// postgres://localhost
+}
+
+// jobRunner starts a new unit of work for every job it runs.
+type jobRunner struct {
+	newUoW oak.Provider[*unitOfWork]
+}
+
+func ExampleProvider() {
+	c := oak.New()
+	next := 0
+	_ = c.Register(func() *unitOfWork {
+		next++
+		return &unitOfWork{ID: next}
+	}, oak.WithLifetime(oak.Transient))
+	_ = c.Register(func(newUoW oak.Provider[*unitOfWork]) *jobRunner {
+		return &jobRunner{newUoW: newUoW}
+	})
+	_ = c.Build()
+
+	runner, _ := oak.Resolve[*jobRunner](c)
+	u1, _ := runner.newUoW()
+	u2, _ := runner.newUoW()
+	fmt.Println(u1.ID, u2.ID)
+	// Output: 1 2
+}
+
+func ExampleOut() {
+	type storage struct {
+		oak.Out
+
+		Config *Config
+		Logger *Logger `oak:"name=storage"`
+	}
+
+	c := oak.New()
+	_ = c.Register(func() storage {
+		return storage{
+			Config: &Config{DSN: "postgres://localhost"},
+			Logger: &Logger{Prefix: "storage"},
+		}
+	})
+	_ = c.Build()
+
+	cfg, _ := oak.Resolve[*Config](c)
+	logger, _ := oak.ResolveNamed[*Logger](c, "storage")
+	fmt.Println(cfg.DSN)
+	fmt.Println(logger.Prefix)
+	// Output:
+	// postgres://localhost
+	// storage
+}
+
+// loudGreeter decorates another Greeter.
+type loudGreeter struct{ next Greeter }
+
+func (g *loudGreeter) Greet() string { return g.next.Greet() + "!" }
+
+func ExampleContainer_Decorate() {
+	c := oak.New()
+	_ = c.Register(func() *englishGreeter { return &englishGreeter{} }, oak.As[Greeter]())
+	_ = c.Decorate(func(g Greeter) Greeter { return &loudGreeter{next: g} })
+	_ = c.Build()
+
+	g, _ := oak.Resolve[Greeter](c)
+	fmt.Println(g.Greet())
+	// Output: hello!
+}
+
 func ExampleContainer_RegisterNamed() {
 	c := oak.New()
 	_ = c.RegisterNamed("dev", func() *Config { return &Config{DSN: "localhost"} })
@@ -88,6 +316,45 @@ func ExampleContainer_RegisterNamed() {
 	// prod-host
 }
 
+func ExampleWithParamName() {
+	c := oak.New()
+	_ = c.RegisterNamed("primary", func() *Config { return &Config{DSN: "primary-host"} })
+	_ = c.Register(func() *Logger { return &Logger{Prefix: "app"} })
+	_ = c.Register(func(cfg *Config, log *Logger) *Database {
+		return &Database{Config: cfg, Logger: log}
+	}, oak.WithParamName(0, "primary"))
+	_ = c.Build()
+
+	db, _ := oak.Resolve[*Database](c)
+	fmt.Println(db.Config.DSN)
+	// Output: primary-host
+}
+
+func ExampleContainer_Invoke() {
+	c := oak.New()
+	_ = c.Register(func() *Config { return &Config{DSN: "postgres://localhost"} })
+
+	// Registered before Build, the function runs once Build has finished.
+	_ = c.Invoke(func(cfg *Config) {
+		fmt.Println("connecting to", cfg.DSN)
+	})
+	_ = c.Build()
+	// Output: connecting to postgres://localhost
+}
+
+func ExampleInvoke() {
+	c := oak.New()
+	_ = c.Register(func() *Config { return &Config{DSN: "postgres://localhost"} })
+	_ = c.Register(func() *Logger { return &Logger{Prefix: "app"} })
+	_ = c.Build()
+
+	db, _ := oak.Invoke[*Database](c, func(cfg *Config, log *Logger) *Database {
+		return &Database{Config: cfg, Logger: log}
+	})
+	fmt.Println(db.Config.DSN)
+	// Output: postgres://localhost
+}
+
 func ExampleResolveNamed() {
 	c := oak.New()
 	_ = c.RegisterNamed("en", func() Greeter { return &englishGreeter{} })
@@ -130,3 +397,118 @@ func ExampleContainer_Shutdown() {
 	// before: false
 	// after: true
 }
+
+func ExampleContainer_Graph() {
+	c := oak.New()
+	_ = c.Register(func() *Config { return &Config{} })
+	_ = c.Register(func(cfg *Config, log *Logger) *Database {
+		return &Database{Config: cfg, Logger: log}
+	})
+	fmt.Print(c.Graph().DOT())
+	// Output:
+	// digraph oak {
+	// 	n0 [label="*oak_test.Config\nsingleton"];
+	// 	n1 [label="*oak_test.Database\nsingleton"];
+	// 	n2 [label="*oak_test.Logger\nmissing", color=red, style=dashed];
+	// 	n1 -> n0;
+	// 	n1 -> n2;
+	// }
+}
+
+func ExampleContainer_Providers() {
+	c := oak.New()
+	_ = c.Register(func() *Config { return &Config{} })
+	_ = c.RegisterNamed("audit", func(*Config) *Logger { return &Logger{} })
+	_ = c.Build()
+
+	for _, p := range c.Providers() {
+		fmt.Println(p.Type, p.Name, p.Lifetime, p.Params, p.Instantiated)
+	}
+	fmt.Println(oak.Has[*Config](c), oak.HasNamed(c, "audit"))
+	// Output:
+	// *oak_test.Config  singleton [] true
+	// *oak_test.Logger audit transient [*oak_test.Config] false
+	// true true
+}
+
+func ExampleMissingProviderError() {
+	c := oak.New()
+	_ = c.Register(func(cfg *Config, log *Logger) *Database {
+		return &Database{Config: cfg, Logger: log}
+	})
+	_ = c.Register(func() *Config { return &Config{} })
+
+	err := c.Build()
+	var missing *oak.MissingProviderError
+	if errors.As(err, &missing) {
+		fmt.Println(missing.Type, "required by", missing.RequiredBy)
+		fmt.Println(missing.Path)
+	}
+	fmt.Println(errors.Is(err, oak.ErrProviderNotFound))
+	// Output:
+	// *oak_test.Logger required by *oak_test.Database
+	// [*oak_test.Database *oak_test.Logger]
+	// true
+}
+
+// httpServer implements oak.Starter and oak.Stopper.
+type httpServer struct{ Addr string }
+
+func (s *httpServer) Start(context.Context) error {
+	fmt.Println("listening on", s.Addr)
+	return nil
+}
+
+func (s *httpServer) Stop(context.Context) error {
+	fmt.Println("stopped", s.Addr)
+	return nil
+}
+
+func ExampleContainer_Start() {
+	c := oak.New()
+	_ = c.Register(func() *connPool {
+		return &connPool{DSN: "postgres://localhost"}
+	}, oak.OnStart(func(_ context.Context, p *connPool) error {
+		fmt.Println("pool ready:", p.DSN)
+		return nil
+	}))
+	_ = c.Register(func(*connPool) *httpServer {
+		return &httpServer{Addr: ":8080"}
+	})
+	_ = c.Build()
+
+	_ = c.Start(context.Background())
+	_ = c.Shutdown(context.Background())
+	// Output:
+	// pool ready: postgres://localhost
+	// listening on :8080
+	// stopped :8080
+}
+
+// unitOfWork is a per-request resource; it is closed when its scope closes.
+type unitOfWork struct{ ID int }
+
+func (u *unitOfWork) Close() error {
+	fmt.Println("closing unit of work", u.ID)
+	return nil
+}
+
+func ExampleContainer_NewScope() {
+	c := oak.New()
+	next := 0
+	_ = c.Register(func() *unitOfWork {
+		next++
+		return &unitOfWork{ID: next}
+	}, oak.WithLifetime(oak.Scoped))
+	_ = c.Build()
+
+	scope := c.NewScope()
+	u1, _ := oak.Resolve[*unitOfWork](scope)
+	u2, _ := oak.Resolve[*unitOfWork](scope)
+	fmt.Println(u1 == u2)
+
+	_ = scope.Close()
+	// Output:
+	// true
+	// closing unit of work 1
+}
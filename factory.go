@@ -0,0 +1,75 @@
+package oak
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Provider is a factory for T. A constructor parameter, or [In] struct field,
+// of type Provider[T] — or of the equivalent plain type func() (T, error) —
+// receives a function that resolves T each time it is called:
+//
+//	func NewHandler(newReq oak.Provider[*RequestBuilder]) *Handler
+//
+//	req, err := h.newReq()
+//
+// Each call follows T's lifetime: a [Transient] provider constructs a fresh
+// instance, a [Singleton] returns the shared one, and a [Scoped] provider
+// returns the instance of the scope the owner was resolved from.
+//
+// Like a [Lazy] edge, a Provider edge is not followed when
+// [Container.Build] looks for cycles, but Build reports a missing provider
+// for T. The factory cannot be called while Build is running and must not be
+// called from inside a constructor.
+type Provider[T any] func() (T, error)
+
+// isProvider reports whether t is a factory type, a [Provider] or a plain
+// func() (T, error), and returns the type it produces.
+func isProvider(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Func || t.NumIn() != 0 || t.NumOut() != 2 || t.Out(1) != errorType {
+		return nil, false
+	}
+	return t.Out(0), true
+}
+
+// unwrapProvider turns d into a factory dependency on the produced type when
+// it is declared as [Provider] or func() (T, error).
+func unwrapProvider(d *dependency) error {
+	inner, ok := isProvider(d.typ)
+	if !ok {
+		return nil
+	}
+	if d.optional {
+		return errors.New("provider: dependency cannot also be optional")
+	}
+	if isParamStruct(inner) {
+		return errors.New("provider: parameter structs cannot be produced")
+	}
+	if isWrapper(inner) {
+		return fmt.Errorf("provider: cannot produce %s", inner)
+	}
+
+	d.wrapper = d.typ
+	d.typ = inner
+	d.lazy = true
+	return nil
+}
+
+// providerDependency returns the factory for d, of type d.wrapper. It
+// resolves d through s when the owner was resolved from a scope, and through
+// the container otherwise.
+func (c *container) providerDependency(d dependency, s *scope) reflect.Value {
+	resolve := c.deferredResolve("provider", d, s)
+
+	return reflect.MakeFunc(d.wrapper, func([]reflect.Value) []reflect.Value {
+		val, err := resolve()
+		if err != nil {
+			return []reflect.Value{reflect.Zero(d.typ), reflect.ValueOf(&err).Elem()}
+		}
+
+		out := reflect.New(d.typ).Elem()
+		out.Set(val)
+		return []reflect.Value{out, reflect.Zero(errorType)}
+	})
+}
@@ -0,0 +1,200 @@
+package oak
+
+import (
+	"errors"
+	"testing"
+)
+
+type testRequestBuilder struct{ ID int }
+
+type testDispatcher struct {
+	NewRequest Provider[*testRequestBuilder]
+}
+
+func TestProvider(t *testing.T) {
+	t.Run("transient target yields fresh instances", func(t *testing.T) {
+		c := New()
+		next := 0
+		mustRegister(t, c, func() *testRequestBuilder {
+			next++
+			return &testRequestBuilder{ID: next}
+		}, WithLifetime(Transient))
+		mustRegister(t, c, func(p Provider[*testRequestBuilder]) *testDispatcher {
+			return &testDispatcher{NewRequest: p}
+		})
+		mustBuild(t, c)
+
+		d, _ := Resolve[*testDispatcher](c)
+		r1, err := d.NewRequest()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		r2, _ := d.NewRequest()
+		if r1 == r2 || r1.ID != 1 || r2.ID != 2 {
+			t.Fatalf("expected two fresh instances, got %+v and %+v", r1, r2)
+		}
+	})
+
+	t.Run("singleton target yields the shared instance", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(func() (*testLogger, error)) *testDispatcher {
+			return &testDispatcher{}
+		})
+		mustBuild(t, c)
+
+		newLogger, err := Invoke[func() (*testLogger, error)](c, func(f func() (*testLogger, error)) func() (*testLogger, error) {
+			return f
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, _ := newLogger()
+		want, _ := Resolve[*testLogger](c)
+		if got != want {
+			t.Fatal("factory should return the container's singleton")
+		}
+	})
+
+	t.Run("resolves from the owner's scope", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		type holder struct{ NewUoW Provider[*testUnitOfWork] }
+		mustRegister(t, c, func(p Provider[*testUnitOfWork]) *holder {
+			return &holder{NewUoW: p}
+		}, WithLifetime(Transient))
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		h, _ := Resolve[*holder](s)
+		got, err := h.NewUoW()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		u, _ := Resolve[*testUnitOfWork](s)
+		if got != u {
+			t.Fatal("factory should resolve from the scope")
+		}
+	})
+
+	t.Run("returns constructor error", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() (*testRequestBuilder, error) {
+			return nil, errors.New("boom")
+		}, WithLifetime(Transient))
+		mustRegister(t, c, func(p Provider[*testRequestBuilder]) *testDispatcher {
+			return &testDispatcher{NewRequest: p}
+		})
+		mustBuild(t, c)
+
+		d, _ := Resolve[*testDispatcher](c)
+		if r, err := d.NewRequest(); err == nil || r != nil {
+			t.Fatalf("expected error and nil instance, got %v, %v", r, err)
+		}
+	})
+
+	t.Run("missing provider fails build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func(Provider[*testRequestBuilder]) *testDispatcher { return &testDispatcher{} })
+
+		if err := c.Build(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("edge is not part of a cycle", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func(Provider[*testRequestBuilder]) *testDispatcher { return &testDispatcher{} })
+		mustRegister(t, c, func(*testDispatcher) *testRequestBuilder { return &testRequestBuilder{} })
+
+		if err := c.Build(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("named", func(t *testing.T) {
+		c := New()
+		mustRegisterNamed(t, c, "audit", func() *testLogger { return &testLogger{Prefix: "audit"} })
+		mustRegister(t, c, func(Provider[*testLogger]) *testDispatcher {
+			return &testDispatcher{}
+		}, WithParamName(0, "audit"))
+		mustBuild(t, c)
+
+		newLogger, err := Invoke[Provider[*testLogger]](c, func(p struct {
+			In
+			NewLogger Provider[*testLogger] `oak:"name=audit"`
+		}) Provider[*testLogger] {
+			return p.NewLogger
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		log, err := newLogger()
+		if err != nil || log.Prefix != "audit" {
+			t.Fatalf("expected audit logger, got %v, %v", log, err)
+		}
+	})
+
+	t.Run("call during build returns ErrNotBuilt", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(p Provider[*testLogger]) (*testDispatcher, error) {
+			_, err := p()
+			return &testDispatcher{}, err
+		})
+
+		if err := c.Build(); !errors.Is(err, ErrNotBuilt) {
+			t.Fatalf("expected ErrNotBuilt, got: %v", err)
+		}
+	})
+
+	t.Run("singleton with scoped target fails build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustRegister(t, c, func(Provider[*testUnitOfWork]) *testDispatcher { return &testDispatcher{} })
+
+		if err := c.Build(); !errors.Is(err, ErrLifetimeMismatch) {
+			t.Fatalf("expected ErrLifetimeMismatch, got: %v", err)
+		}
+	})
+
+	t.Run("singleton with scoped target through a transient fails build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustRegister(t, c, func(*testUnitOfWork) *testTracer { return &testTracer{} }, WithLifetime(Transient))
+		mustRegister(t, c, func(Provider[*testTracer]) *testDispatcher { return &testDispatcher{} })
+
+		if err := c.Build(); !errors.Is(err, ErrLifetimeMismatch) {
+			t.Fatalf("expected ErrLifetimeMismatch, got: %v", err)
+		}
+	})
+
+	t.Run("transient with scoped target through a transient builds", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustRegister(t, c, func(*testUnitOfWork) *testTracer { return &testTracer{} }, WithLifetime(Transient))
+		mustRegister(t, c, func(Provider[*testTracer]) *testDispatcher { return &testDispatcher{} }, WithLifetime(Transient))
+		mustBuild(t, c)
+	})
+
+	t.Run("rejects invalid wrapping", func(t *testing.T) {
+		type params struct {
+			In
+			NewLogger Provider[*testLogger] `oak:"optional"`
+		}
+
+		for _, ctor := range []interface{}{
+			func(params) *testDispatcher { return nil },
+			func(Provider[Lazy[*testLogger]]) *testDispatcher { return nil },
+			func(Lazy[Provider[*testLogger]]) *testDispatcher { return nil },
+		} {
+			if err := New().Register(ctor); err == nil {
+				t.Fatalf("expected error for %T", ctor)
+			}
+		}
+	})
+}
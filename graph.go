@@ -0,0 +1,279 @@
+package oak
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Graph is a snapshot of the providers registered with a container and the
+// dependencies between them, as returned by [Container.Graph]. It can be
+// rendered with [Graph.DOT], [Graph.Mermaid] and [Graph.JSON].
+type Graph struct {
+	// Nodes lists the providers in registration order, followed by a node
+	// for every missing dependency.
+	Nodes []GraphNode `json:"nodes"`
+
+	// Edges lists the dependencies of each node, in node and parameter
+	// order, pointing from the dependent to the dependency.
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a provider in a [Graph].
+type GraphNode struct {
+	// ID identifies the node within its graph.
+	ID string
+
+	// Type is the type the provider returns. Name and Group are set for
+	// named providers and value group members, and Interfaces lists the
+	// types it is also registered under with [As].
+	Type       reflect.Type
+	Name       string
+	Group      string
+	Interfaces []reflect.Type
+
+	Lifetime Lifetime
+
+	// Constructor is the fully qualified name of the provider's constructor.
+	// The registration site is left out so the graph is the same on every
+	// machine; [Container.Providers] has it.
+	Constructor string
+
+	// Missing is set for a dependency that no provider satisfies. Only
+	// ID, Type and Name are meaningful then.
+	Missing bool
+}
+
+// GraphEdge is a dependency in a [Graph].
+type GraphEdge struct {
+	// From and To are the IDs of the dependent and dependency nodes.
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Group is the value group the dependency was injected through, if any.
+	Group string `json:"group,omitempty"`
+
+	// Optional is set for [Optional] dependencies, and Lazy for [Lazy] and
+	// [Provider] ones, which Build does not follow when looking for
+	// cycles.
+	Optional bool `json:"optional,omitempty"`
+	Lazy     bool `json:"lazy,omitempty"`
+}
+
+// MarshalJSON encodes the node with its types and lifetime as strings.
+func (n GraphNode) MarshalJSON() ([]byte, error) {
+	out := struct {
+		ID          string   `json:"id"`
+		Type        string   `json:"type"`
+		Name        string   `json:"name,omitempty"`
+		Group       string   `json:"group,omitempty"`
+		Interfaces  []string `json:"interfaces,omitempty"`
+		Lifetime    string   `json:"lifetime,omitempty"`
+		Constructor string   `json:"constructor,omitempty"`
+		Missing     bool     `json:"missing,omitempty"`
+	}{
+		ID:          n.ID,
+		Type:        typeName(n.Type),
+		Name:        n.Name,
+		Group:       n.Group,
+		Constructor: n.Constructor,
+		Missing:     n.Missing,
+	}
+	for _, t := range n.Interfaces {
+		out.Interfaces = append(out.Interfaces, t.String())
+	}
+	if !n.Missing {
+		out.Lifetime = n.Lifetime.String()
+	}
+	return json.Marshal(out)
+}
+
+func (c *container) Graph() *Graph {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	g := &Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	ids := make(map[*provider]string, len(c.registered))
+	for _, p := range c.registered {
+		id := fmt.Sprintf("n%d", len(g.Nodes))
+		ids[p] = id
+		g.Nodes = append(g.Nodes, GraphNode{
+			ID:          id,
+			Type:        p.outType,
+			Name:        p.name,
+			Group:       p.group,
+			Interfaces:  append([]reflect.Type(nil), p.interfaces...),
+			Lifetime:    p.lifetime,
+			Constructor: p.funcName,
+		})
+	}
+
+	// missing holds the node IDs of unsatisfied dependencies, keyed by
+	// their type or name, so each appears once.
+	missing := make(map[string]string)
+	seen := make(map[GraphEdge]bool)
+	for _, p := range c.registered {
+		for _, d := range c.edges(p) {
+			edge := GraphEdge{From: ids[p], Group: d.group, Optional: d.optional, Lazy: d.lazy}
+
+			targets, err := c.dependencyTargets(d)
+			if errors.Is(err, ErrProviderNotFound) {
+				key := "type " + d.typ.String()
+				if d.name != "" {
+					key = "name " + d.name
+				}
+				id, ok := missing[key]
+				if !ok {
+					id = fmt.Sprintf("n%d", len(g.Nodes))
+					missing[key] = id
+					g.Nodes = append(g.Nodes, GraphNode{ID: id, Type: d.typ, Name: d.name, Missing: true})
+				}
+				targets = nil
+				edge.To = id
+			} else if err != nil {
+				// A mismatched named or group member type; Build reports
+				// it, and the graph leaves the edge out.
+				continue
+			}
+
+			if edge.To != "" && !seen[edge] {
+				seen[edge] = true
+				g.Edges = append(g.Edges, edge)
+			}
+			for _, q := range targets {
+				edge.To = ids[q]
+				if !seen[edge] {
+					seen[edge] = true
+					g.Edges = append(g.Edges, edge)
+				}
+			}
+		}
+	}
+	return g
+}
+
+// JSON encodes the graph as indented JSON, suitable for diffing. Types and
+// lifetimes are written as strings.
+func (g *Graph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// DOT renders the graph in the Graphviz DOT language. Missing dependencies
+// are drawn in red, and lazy and optional edges are dashed.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph oak {\n")
+	for _, n := range g.Nodes {
+		attrs := "label=" + dotQuote(n.labelLines()...)
+		if n.Missing {
+			attrs += ", color=red, style=dashed"
+		}
+		fmt.Fprintf(&b, "\t%s [%s];\n", n.ID, attrs)
+	}
+	for _, e := range g.Edges {
+		var attrs []string
+		if label := e.label(); label != "" {
+			attrs = append(attrs, "label="+dotQuote(label))
+		}
+		if e.Lazy || e.Optional {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, "\t%s -> %s [%s];\n", e.From, e.To, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(&b, "\t%s -> %s;\n", e.From, e.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Missing dependencies
+// get the missing class, and lazy and optional edges are dotted.
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		lines := n.labelLines()
+		for i, l := range lines {
+			lines[i] = mermaidEscape(l)
+		}
+		fmt.Fprintf(&b, "\t%s[\"%s\"]\n", n.ID, strings.Join(lines, "<br/>"))
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Lazy || e.Optional {
+			arrow = "-.->"
+		}
+		if label := e.label(); label != "" {
+			fmt.Fprintf(&b, "\t%s %s|\"%s\"| %s\n", e.From, arrow, mermaidEscape(label), e.To)
+		} else {
+			fmt.Fprintf(&b, "\t%s %s %s\n", e.From, arrow, e.To)
+		}
+	}
+	var missing []string
+	for _, n := range g.Nodes {
+		if n.Missing {
+			missing = append(missing, n.ID)
+		}
+	}
+	if len(missing) > 0 {
+		b.WriteString("\tclassDef missing stroke:#d00,stroke-dasharray:4\n")
+		fmt.Fprintf(&b, "\tclass %s missing\n", strings.Join(missing, ","))
+	}
+	return b.String()
+}
+
+// labelLines returns the lines describing n in a rendered graph.
+func (n GraphNode) labelLines() []string {
+	lines := []string{typeName(n.Type)}
+	switch {
+	case n.Name != "":
+		lines = append(lines, fmt.Sprintf("name: %s", n.Name))
+	case n.Group != "":
+		lines = append(lines, fmt.Sprintf("group: %s", n.Group))
+	}
+	if n.Missing {
+		return append(lines, "missing")
+	}
+	return append(lines, n.Lifetime.String())
+}
+
+// label returns the annotations of e in a rendered graph, or "".
+func (e GraphEdge) label() string {
+	var parts []string
+	if e.Group != "" {
+		parts = append(parts, "group: "+e.Group)
+	}
+	if e.Optional {
+		parts = append(parts, "optional")
+	}
+	if e.Lazy {
+		parts = append(parts, "lazy")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
+
+// dotQuote quotes lines as a DOT string, one line each.
+func dotQuote(lines ...string) string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	for i, l := range lines {
+		lines[i] = escape.Replace(l)
+	}
+	return `"` + strings.Join(lines, `\n`) + `"`
+}
+
+// mermaidEscape replaces the characters Mermaid treats specially in a quoted
+// label with entity codes.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
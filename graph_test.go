@@ -0,0 +1,190 @@
+package oak
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestGraphContainer registers a small graph with a named scoped
+// provider, a group member, a lazy edge and a missing dependency.
+func newTestGraphContainer(t *testing.T) Container {
+	t.Helper()
+	c := New()
+	mustRegister(t, c, newTestLogger)
+	mustRegisterNamed(t, c, "orders", newTestOrderService, WithLifetime(Scoped))
+	mustRegister(t, c, func(Lazy[*testLogger], *testTracer) *testConfig {
+		return &testConfig{}
+	}, InGroup("configs"))
+	return c
+}
+
+func TestGraph(t *testing.T) {
+	t.Run("nodes and edges", func(t *testing.T) {
+		g := newTestGraphContainer(t).Graph()
+
+		want := []GraphNode{
+			{ID: "n0", Type: reflect.TypeOf(&testLogger{}), Lifetime: Singleton,
+				Constructor: "github.com/ARTM2000/oak.newTestLogger"},
+			{ID: "n1", Type: reflect.TypeOf(&testOrderService{}), Name: "orders", Lifetime: Scoped,
+				Constructor: "github.com/ARTM2000/oak.newTestOrderService"},
+			{ID: "n2", Type: reflect.TypeOf(&testConfig{}), Group: "configs", Lifetime: Singleton,
+				Constructor: "github.com/ARTM2000/oak.newTestGraphContainer.func1"},
+			{ID: "n3", Type: reflect.TypeOf(&testTracer{}), Missing: true},
+		}
+		if !reflect.DeepEqual(g.Nodes, want) {
+			t.Fatalf("unexpected nodes: %+v", g.Nodes)
+		}
+
+		wantEdges := []GraphEdge{
+			{From: "n1", To: "n0"},
+			{From: "n2", To: "n0", Lazy: true},
+			{From: "n2", To: "n3"},
+		}
+		if !reflect.DeepEqual(g.Edges, wantEdges) {
+			t.Fatalf("unexpected edges: %+v", g.Edges)
+		}
+	})
+
+	t.Run("interfaces share one node", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, As[testService]())
+		mustRegister(t, c, func(testService) *testConfig { return nil })
+
+		g := c.Graph()
+		if len(g.Nodes) != 3 || len(g.Nodes[1].Interfaces) != 1 {
+			t.Fatalf("expected 3 nodes with one interface, got %+v", g.Nodes)
+		}
+		if g.Edges[1] != (GraphEdge{From: "n2", To: "n1"}) {
+			t.Fatalf("expected edge to the bound provider, got %+v", g.Edges)
+		}
+
+		// The nodes are a snapshot: editing one leaves the registration
+		// alone.
+		g.Nodes[1].Interfaces[0] = reflect.TypeOf(&testLogger{})
+		if got := c.Graph().Nodes[1].Interfaces[0]; got != reflect.TypeOf((*testService)(nil)).Elem() {
+			t.Fatalf("expected the provider's interface to be unchanged, got %s", got)
+		}
+	})
+
+	t.Run("group edges reach every member", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() *testLogger { return &testLogger{} }, InGroup("logs"))
+		mustRegister(t, c, func() *testLogger { return &testLogger{} }, InGroup("logs"))
+		mustRegister(t, c, func([]*testLogger) *testConfig { return nil }, WithParamGroup(0, "logs"))
+
+		g := c.Graph()
+		want := []GraphEdge{
+			{From: "n2", To: "n0", Group: "logs"},
+			{From: "n2", To: "n1", Group: "logs"},
+		}
+		if !reflect.DeepEqual(g.Edges, want) {
+			t.Fatalf("unexpected edges: %+v", g.Edges)
+		}
+	})
+
+	t.Run("includes decorator dependencies", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestConfig)
+		if err := c.Decorate(func(l *testLogger, _ *testConfig) *testLogger { return l }); err != nil {
+			t.Fatalf("Decorate: %v", err)
+		}
+
+		g := c.Graph()
+		if len(g.Edges) != 1 || g.Edges[0] != (GraphEdge{From: "n0", To: "n1"}) {
+			t.Fatalf("expected decorator edge, got %+v", g.Edges)
+		}
+	})
+
+	t.Run("missing named dependency", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestOrderService, WithParamName(0, "audit"))
+
+		g := c.Graph()
+		if n := g.Nodes[1]; !n.Missing || n.Name != "audit" {
+			t.Fatalf("expected missing named node, got %+v", n)
+		}
+	})
+}
+
+func TestGraph_DOT(t *testing.T) {
+	got := newTestGraphContainer(t).Graph().DOT()
+	want := `digraph oak {
+	n0 [label="*oak.testLogger\nsingleton"];
+	n1 [label="*oak.testOrderService\nname: orders\nscoped"];
+	n2 [label="*oak.testConfig\ngroup: configs\nsingleton"];
+	n3 [label="*oak.testTracer\nmissing", color=red, style=dashed];
+	n1 -> n0;
+	n2 -> n0 [label="lazy", style=dashed];
+	n2 -> n3;
+}
+`
+	if got != want {
+		t.Fatalf("unexpected DOT:\n%s", got)
+	}
+}
+
+func TestGraph_Mermaid(t *testing.T) {
+	got := newTestGraphContainer(t).Graph().Mermaid()
+	want := `flowchart LR
+	n0["*oak.testLogger<br/>singleton"]
+	n1["*oak.testOrderService<br/>name: orders<br/>scoped"]
+	n2["*oak.testConfig<br/>group: configs<br/>singleton"]
+	n3["*oak.testTracer<br/>missing"]
+	n1 --> n0
+	n2 -.->|"lazy"| n0
+	n2 --> n3
+	classDef missing stroke:#d00,stroke-dasharray:4
+	class n3 missing
+`
+	if got != want {
+		t.Fatalf("unexpected Mermaid:\n%s", got)
+	}
+}
+
+func TestGraph_JSON(t *testing.T) {
+	data, err := newTestGraphContainer(t).Graph().JSON()
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+
+	var got struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+		Edges []map[string]interface{} `json:"edges"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(got.Nodes) != 4 || len(got.Edges) != 3 {
+		t.Fatalf("unexpected graph: %s", data)
+	}
+
+	want := map[string]interface{}{"id": "n1", "type": "*oak.testOrderService", "name": "orders", "lifetime": "scoped",
+		"constructor": "github.com/ARTM2000/oak.newTestOrderService"}
+	if !reflect.DeepEqual(got.Nodes[1], want) {
+		t.Fatalf("unexpected node: %v", got.Nodes[1])
+	}
+	if missing := got.Nodes[3]; missing["missing"] != true || missing["lifetime"] != nil {
+		t.Fatalf("unexpected missing node: %v", missing)
+	}
+	if !strings.Contains(string(data), "\n  \"nodes\"") {
+		t.Fatalf("expected indented JSON, got: %s", data)
+	}
+}
+
+func TestGraph_Escaping(t *testing.T) {
+	g := &Graph{Nodes: []GraphNode{{ID: "n0", Type: reflect.TypeOf(struct {
+		A int `json:"a"`
+	}{}), Lifetime: Singleton}}}
+
+	// The type's string form quotes the tag: struct { A int "json:\"a\"" }.
+	if dot := g.DOT(); !strings.Contains(dot, `int \"json:\\\"a\\\"\"`) {
+		t.Fatalf("expected escaped quotes, got: %s", dot)
+	}
+	if m := g.Mermaid(); strings.Contains(m, `"json`) {
+		t.Fatalf("expected escaped quotes, got: %s", m)
+	}
+}
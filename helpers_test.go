@@ -1,6 +1,7 @@
 package oak
 
 import (
+	"context"
 	"errors"
 	"testing"
 )
@@ -100,6 +101,28 @@ func (c *testClosable) Close() error {
 	return nil
 }
 
+// testGracefulServer implements Shutdowner and io.Closer, like http.Server.
+// Shutdown blocks until ctx is done when Block is set.
+type testGracefulServer struct {
+	Block  bool
+	Ctx    context.Context
+	Closed bool
+}
+
+func (s *testGracefulServer) Shutdown(ctx context.Context) error {
+	s.Ctx = ctx
+	if s.Block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (s *testGracefulServer) Close() error {
+	s.Closed = true
+	return nil
+}
+
 // testFailCloser implements io.Closer but returns an error.
 type testFailCloser struct{}
 
@@ -0,0 +1,118 @@
+package oak
+
+import "reflect"
+
+// ProviderInfo describes a registered provider, as returned by
+// [Container.Providers].
+type ProviderInfo struct {
+	// Type is the type the constructor returns. Name and Group are set for
+	// named providers and value group members, and Interfaces lists the
+	// types the provider is also registered under with [As].
+	Type       reflect.Type
+	Name       string
+	Group      string
+	Interfaces []reflect.Type
+
+	Lifetime Lifetime
+
+	// Lazy is set for singletons registered with [WithLazy].
+	Lazy bool
+
+	// Params lists the constructor's parameter types, as declared: an [In]
+	// struct or a [Lazy] wrapper appears as itself.
+	Params []reflect.Type
+
+	// Instantiated reports whether the container holds an instance of the
+	// provider, which only singletons do once constructed.
+	Instantiated bool
+
+	// Closer reports whether the instance is tracked for
+	// [Container.Shutdown], as a [Shutdowner], an [io.Closer], or through a
+	// cleanup function.
+	Closer bool
+
+	// Constructor is the constructor's fully qualified function name, and
+	// File and Line locate the Register or RegisterNamed call. Fields of an
+	// [Out] struct share those of the constructor returning it.
+	Constructor string
+	File        string
+	Line        int
+}
+
+func (c *container) Providers() []ProviderInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	c.trackMu.Lock()
+	instantiated := make(map[*provider]bool, len(c.constructed))
+	for _, comp := range c.constructed {
+		instantiated[comp.p] = true
+	}
+	closing := make(map[*provider]bool, len(c.closers))
+	for _, cl := range c.closers {
+		closing[cl.p] = true
+	}
+	c.trackMu.Unlock()
+
+	infos := make([]ProviderInfo, 0, len(c.registered))
+	for _, p := range c.registered {
+		fnType := p.constructor.Type()
+		params := make([]reflect.Type, fnType.NumIn())
+		for i := range params {
+			params[i] = fnType.In(i)
+		}
+
+		infos = append(infos, ProviderInfo{
+			Type:         p.outType,
+			Name:         p.name,
+			Group:        p.group,
+			Interfaces:   append([]reflect.Type(nil), p.interfaces...),
+			Lifetime:     p.lifetime,
+			Lazy:         p.lazy != nil,
+			Params:       params,
+			Instantiated: instantiated[p],
+			Closer:       closing[p],
+			Constructor:  p.funcName,
+			File:         p.file,
+			Line:         p.line,
+		})
+	}
+	return infos
+}
+
+// Has reports whether c has a provider resolvable as T, registered for T
+// itself or bound to it with [As]. Named providers and value group members
+// do not count. It does not check that the provider's dependencies exist;
+// [Container.Build] does.
+//
+//	if !oak.Has[*Cache](c) {
+//	    c.Register(NewNoopCache)
+//	}
+func Has[T any](c Container) bool {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for _, info := range c.Providers() {
+		if info.Name != "" || info.Group != "" {
+			continue
+		}
+		if info.Type == t {
+			return true
+		}
+		for _, iface := range info.Interfaces {
+			if iface == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasNamed reports whether c has a provider registered under name with
+// [Container.RegisterNamed] or a name tag on an [Out] struct field.
+func HasNamed(c Container, name string) bool {
+	for _, info := range c.Providers() {
+		if info.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package oak
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProviders(t *testing.T) {
+	t.Run("describes providers in registration order", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegisterNamed(t, c, "orders", newTestOrderService)
+		mustRegister(t, c, newTestConfig, WithLazy())
+		mustRegister(t, c, func() *testLogger { return &testLogger{} }, InGroup("logs"))
+
+		infos := c.Providers()
+		for i := range infos {
+			// Registration sites are covered by the "records registration
+			// sites" test.
+			infos[i].Constructor, infos[i].File, infos[i].Line = "", "", 0
+		}
+		want := []ProviderInfo{
+			{Type: reflect.TypeOf(&testLogger{}), Lifetime: Singleton, Params: []reflect.Type{}},
+			{Type: reflect.TypeOf(&testOrderService{}), Name: "orders", Lifetime: Transient,
+				Params: []reflect.Type{reflect.TypeOf(&testLogger{})}},
+			{Type: reflect.TypeOf(&testConfig{}), Lifetime: Singleton, Lazy: true, Params: []reflect.Type{}},
+			{Type: reflect.TypeOf(&testLogger{}), Group: "logs", Lifetime: Singleton, Params: []reflect.Type{}},
+		}
+		if !reflect.DeepEqual(infos, want) {
+			t.Fatalf("unexpected providers:\n got %+v\nwant %+v", infos, want)
+		}
+	})
+
+	t.Run("records registration sites", func(t *testing.T) {
+		c := New()
+		file, line := here()
+		if err := c.Register(newTestLogger); err != nil {
+			t.Fatal(err)
+		}
+		mustRegister(t, c, newTestConfig)
+
+		infos := c.Providers()
+		if got := infos[0]; got.Constructor != "github.com/ARTM2000/oak.newTestLogger" || got.File != file || got.Line != line {
+			t.Fatalf("expected newTestLogger at %s:%d, got %s at %s:%d", file, line, got.Constructor, got.File, got.Line)
+		}
+		if got := infos[1]; !strings.HasSuffix(got.File, "helpers_test.go") {
+			t.Fatalf("expected the helper calling Register, got %s", got.File)
+		}
+	})
+
+	t.Run("lists interfaces once", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, As[testService]())
+
+		infos := c.Providers()
+		if len(infos) != 2 {
+			t.Fatalf("expected 2 providers, got %d", len(infos))
+		}
+		if got := infos[1].Interfaces; len(got) != 1 || got[0] != reflect.TypeOf((*testService)(nil)).Elem() {
+			t.Fatalf("expected testService interface, got %v", got)
+		}
+	})
+
+	t.Run("reports instantiated singletons and closers", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() *testClosable { return &testClosable{} })
+		mustRegister(t, c, newTestLogger, WithCleanup(func(*testLogger) error { return nil }))
+		mustRegister(t, c, newTestConfig)
+		mustRegister(t, c, func() *testFailCloser { return &testFailCloser{} }, WithLazy())
+		mustRegister(t, c, newTestOrderService, WithLifetime(Transient))
+
+		for _, info := range c.Providers() {
+			if info.Instantiated || info.Closer {
+				t.Fatalf("nothing should be instantiated before Build: %+v", info)
+			}
+		}
+
+		mustBuild(t, c)
+		_, _ = Resolve[*testOrderService](c)
+		check := func(want [][2]bool) {
+			t.Helper()
+			for i, info := range c.Providers() {
+				if got := [2]bool{info.Instantiated, info.Closer}; got != want[i] {
+					t.Fatalf("provider %s: expected instantiated, closer = %v, got %v", info.Type, want[i], got)
+				}
+			}
+		}
+		check([][2]bool{{true, true}, {true, true}, {true, false}, {false, false}, {false, false}})
+
+		_, _ = Resolve[*testFailCloser](c)
+		check([][2]bool{{true, true}, {true, true}, {true, false}, {true, true}, {false, false}})
+
+		_ = c.Shutdown(context.Background())
+	})
+}
+
+func TestHas(t *testing.T) {
+	c := New()
+	mustRegister(t, c, newTestLogger)
+	mustRegister(t, c, newTestOrderService, As[testService]())
+	mustRegisterNamed(t, c, "cfg", newTestConfig)
+	mustRegister(t, c, func() *testTracer { return &testTracer{} }, InGroup("tracers"))
+
+	if !Has[*testLogger](c) || !Has[*testOrderService](c) || !Has[testService](c) {
+		t.Fatal("expected registered types to be found")
+	}
+	if Has[*testConfig](c) {
+		t.Fatal("named provider should not count for Has")
+	}
+	if Has[*testTracer](c) {
+		t.Fatal("group member should not count for Has")
+	}
+	if !HasNamed(c, "cfg") || HasNamed(c, "missing") {
+		t.Fatal("HasNamed should find only registered names")
+	}
+}
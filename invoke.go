@@ -0,0 +1,164 @@
+package oak
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// invocation is a function whose parameters are injected by the container,
+// created by [Container.Invoke].
+type invocation struct {
+	fn   reflect.Value
+	deps []dependency
+}
+
+func newInvocation(fn interface{}) (*invocation, error) {
+	val := reflect.ValueOf(fn)
+	if !val.IsValid() || val.Kind() != reflect.Func {
+		return nil, errors.New("invoke: argument must be a function")
+	}
+
+	deps, err := newDependencies(val.Type(), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invoke %s: %w", val.Type(), err)
+	}
+
+	return &invocation{fn: val, deps: deps}, nil
+}
+
+func (c *container) Invoke(fn interface{}) error {
+	inv, err := newInvocation(fn)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if !c.built {
+		c.invocations = append(c.invocations, inv)
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+
+	// Once built the container never goes back, so a read-lock is enough to
+	// resolve the arguments. It is released before the call: a function that
+	// blocks, such as a server's serve loop, must not keep Shutdown waiting.
+	c.mu.RLock()
+	args, err := c.invocationArgs(inv, nil)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	_, err = inv.call(args)
+	return err
+}
+
+// invoke resolves the parameters of inv, calls it and returns its results,
+// for Build, which holds the write lock throughout.
+func (c *container) invoke(inv *invocation, s *scope) ([]reflect.Value, error) {
+	args, err := c.invocationArgs(inv, s)
+	if err != nil {
+		return nil, err
+	}
+	return inv.call(args)
+}
+
+// invocationArgs resolves the parameters of inv. The caller holds c.mu.
+func (c *container) invocationArgs(inv *invocation, s *scope) ([]reflect.Value, error) {
+	args, err := c.arguments(inv.fn.Type(), inv.deps, s)
+	if err != nil {
+		return nil, fmt.Errorf("invoke %s: %w", inv.fn.Type(), err)
+	}
+	return args, nil
+}
+
+// call calls inv with args and returns its results. If the last result is a
+// non-nil error it is returned as well.
+func (inv *invocation) call(args []reflect.Value) ([]reflect.Value, error) {
+	fnType := inv.fn.Type()
+	results := inv.fn.Call(args)
+	if n := len(results); n > 0 && fnType.Out(n-1) == errorType && !results[n-1].IsNil() {
+		return results, results[n-1].Interface().(error)
+	}
+
+	return results, nil
+}
+
+// validateInvocation checks that every dependency of inv can be resolved from
+// the container, walking them with the same state as the rest of the graph.
+// The problems found are recorded in g, prefixed with the function's type.
+func (c *container) validateInvocation(g *buildGraph, inv *invocation) {
+	g.walk(fmt.Sprintf("invoke %s", inv.fn.Type()), func() {
+		for _, d := range inv.deps {
+			targets, err := c.dependencyTargets(d)
+			if err != nil {
+				g.fail(err)
+				continue
+			}
+			if d.lazy {
+				continue
+			}
+			for _, q := range targets {
+				c.visit(g, d.typ, q, nil)
+				if g.scoped[q] {
+					g.fail(fmt.Errorf("%w: %s", ErrScopeRequired, d.typ))
+				}
+			}
+		}
+	})
+}
+
+// Invoke is a generic variant of [Container.Invoke] for functions that
+// return T or (T, error). The function's parameters are resolved from the
+// built container and its result is returned:
+//
+//	srv, err := oak.Invoke[*http.Server](c, func(cfg *Config, h http.Handler) *http.Server {
+//	    return &http.Server{Addr: cfg.Addr, Handler: h}
+//	})
+//
+// Unlike the method, it cannot defer the call and returns [ErrNotBuilt]
+// before [Container.Build].
+func Invoke[T any](c Container, fn interface{}) (T, error) {
+	var zero T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	ci, ok := c.(*container)
+	if !ok {
+		return zero, fmt.Errorf("invoke: unsupported container %T", c)
+	}
+
+	inv, err := newInvocation(fn)
+	if err != nil {
+		return zero, err
+	}
+
+	fnType := inv.fn.Type()
+	if fnType.NumOut() == 0 || fnType.NumOut() > 2 ||
+		!fnType.Out(0).AssignableTo(t) ||
+		(fnType.NumOut() == 2 && fnType.Out(1) != errorType) {
+		return zero, fmt.Errorf("invoke %s: function must return (%s) or (%s, error)", fnType, t, t)
+	}
+
+	ci.mu.RLock()
+	if !ci.built {
+		ci.mu.RUnlock()
+		return zero, ErrNotBuilt
+	}
+	args, err := ci.invocationArgs(inv, nil)
+	ci.mu.RUnlock()
+	if err != nil {
+		return zero, err
+	}
+
+	results, err := inv.call(args)
+	if err != nil {
+		return zero, err
+	}
+
+	// The result type was checked above, so the assertion only fails for a
+	// nil interface, which leaves the zero value.
+	out, _ := results[0].Interface().(T)
+	return out, nil
+}
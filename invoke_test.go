@@ -0,0 +1,278 @@
+package oak
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testBlockingServer implements Shutdowner; Serve blocks until Shutdown is
+// called, like http.Server.ListenAndServe.
+type testBlockingServer struct {
+	serving chan struct{}
+	done    chan struct{}
+}
+
+func newTestBlockingServer() *testBlockingServer {
+	return &testBlockingServer{serving: make(chan struct{}), done: make(chan struct{})}
+}
+
+func (s *testBlockingServer) Serve() error {
+	close(s.serving)
+	<-s.done
+	return nil
+}
+
+func (s *testBlockingServer) Shutdown(context.Context) error {
+	close(s.done)
+	return nil
+}
+
+// shutdownWhileServing runs invoke, which must call Serve on the container's
+// *testBlockingServer, and checks that Shutdown stops it.
+func shutdownWhileServing(t *testing.T, invoke func(c Container) error) {
+	t.Helper()
+	c := New()
+	mustRegister(t, c, newTestBlockingServer)
+	mustRegister(t, c, newTestLogger, WithLifetime(Transient))
+	mustBuild(t, c)
+	srv, _ := Resolve[*testBlockingServer](c)
+
+	invoked := make(chan error, 1)
+	go func() { invoked <- invoke(c) }()
+	<-srv.serving
+
+	shut := make(chan error, 1)
+	go func() { shut <- c.Shutdown(context.Background()) }()
+	select {
+	case err := <-shut:
+		if err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown blocked by the invoked function")
+	}
+	if err := <-invoked; err != nil {
+		t.Fatalf("invoke: %v", err)
+	}
+}
+
+func TestContainer_Invoke(t *testing.T) {
+	t.Run("injects parameters after build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestConfig)
+		mustBuild(t, c)
+
+		want, _ := Resolve[*testLogger](c)
+
+		var got *testLogger
+		var cfg *testConfig
+		err := c.Invoke(func(log *testLogger, c *testConfig) {
+			got, cfg = log, c
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want || cfg == nil {
+			t.Fatal("invoked function should receive the container's instances")
+		}
+	})
+
+	t.Run("returns the function's error", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustBuild(t, c)
+
+		err := c.Invoke(func(*testLogger) error { return errors.New("boom") })
+		if err == nil || err.Error() != "boom" {
+			t.Fatalf("expected 'boom', got: %v", err)
+		}
+	})
+
+	t.Run("missing dependency after build", func(t *testing.T) {
+		c := New()
+		mustBuild(t, c)
+
+		err := c.Invoke(func(*testLogger) {})
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("before build runs at the end of build in order", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegister(t, c, func() *testLogger {
+			order = append(order, "logger")
+			return &testLogger{}
+		})
+
+		if err := c.Invoke(func(*testLogger) { order = append(order, "first") }); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := c.Invoke(func(*testLogger) { order = append(order, "second") }); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(order) != 0 {
+			t.Fatalf("invoke before build should not run, got %v", order)
+		}
+
+		mustBuild(t, c)
+
+		if strings.Join(order, ",") != "logger,first,second" {
+			t.Fatalf("expected [logger first second], got %v", order)
+		}
+	})
+
+	t.Run("before build propagates the function's error", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		_ = c.Invoke(func(*testLogger) error { return errors.New("boom") })
+
+		if err := c.Build(); err == nil || !strings.Contains(err.Error(), "boom") {
+			t.Fatalf("expected 'boom' in error, got: %v", err)
+		}
+	})
+
+	t.Run("before build with missing dependency fails build", func(t *testing.T) {
+		c := New()
+		ran := false
+		_ = c.Invoke(func(*testLogger) { ran = true })
+
+		err := c.Build()
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+		if ran {
+			t.Fatal("function should not run when validation fails")
+		}
+	})
+
+	t.Run("before build with scoped dependency fails build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		_ = c.Invoke(func(*testUnitOfWork) {})
+
+		if err := c.Build(); !errors.Is(err, ErrScopeRequired) {
+			t.Fatalf("expected ErrScopeRequired, got: %v", err)
+		}
+	})
+
+	t.Run("parameter struct", func(t *testing.T) {
+		type params struct {
+			In
+
+			Logger *testLogger
+			Config *testConfig `oak:"optional"`
+		}
+
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustBuild(t, c)
+
+		var got params
+		if err := c.Invoke(func(p params) { got = p }); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Logger == nil || got.Config != nil {
+			t.Fatalf("unexpected params: %+v", got)
+		}
+	})
+
+	t.Run("does not hold the container while the function runs", func(t *testing.T) {
+		shutdownWhileServing(t, func(c Container) error {
+			return c.Invoke(func(srv *testBlockingServer) error { return srv.Serve() })
+		})
+	})
+
+	t.Run("function may resolve from the container", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustBuild(t, c)
+
+		err := c.Invoke(func(p Provider[*testLogger]) error {
+			_, err := p()
+			return err
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects non-function", func(t *testing.T) {
+		c := New()
+		if err := c.Invoke("not a function"); err == nil {
+			t.Fatal("expected error for non-function")
+		}
+		if err := c.Invoke(nil); err == nil {
+			t.Fatal("expected error for nil")
+		}
+	})
+}
+
+func TestInvoke(t *testing.T) {
+	t.Run("returns the result", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustBuild(t, c)
+
+		svc, err := Invoke[testService](c, func(log *testLogger) *testOrderService {
+			return &testOrderService{Logger: log}
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if svc.Name() != "order" {
+			t.Fatalf("expected order service, got %v", svc)
+		}
+	})
+
+	t.Run("returns the function's error", func(t *testing.T) {
+		c := New()
+		mustBuild(t, c)
+
+		_, err := Invoke[*testLogger](c, func() (*testLogger, error) {
+			return nil, errors.New("boom")
+		})
+		if err == nil || err.Error() != "boom" {
+			t.Fatalf("expected 'boom', got: %v", err)
+		}
+	})
+
+	t.Run("does not hold the container while the function runs", func(t *testing.T) {
+		shutdownWhileServing(t, func(c Container) error {
+			_, err := Invoke[*testLogger](c, func(srv *testBlockingServer, log *testLogger) (*testLogger, error) {
+				return log, srv.Serve()
+			})
+			return err
+		})
+	})
+
+	t.Run("before build returns ErrNotBuilt", func(t *testing.T) {
+		c := New()
+
+		_, err := Invoke[*testLogger](c, newTestLogger)
+		if !errors.Is(err, ErrNotBuilt) {
+			t.Fatalf("expected ErrNotBuilt, got: %v", err)
+		}
+	})
+
+	t.Run("rejects mismatched result", func(t *testing.T) {
+		c := New()
+		mustBuild(t, c)
+
+		for _, fn := range []interface{}{
+			func() {},
+			newTestConfig,
+			func() (*testLogger, int) { return nil, 0 },
+		} {
+			if _, err := Invoke[*testLogger](c, fn); err == nil {
+				t.Fatalf("expected error for %T", fn)
+			}
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package oak
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Lazy defers the resolution of a dependency until it is first needed. A
+// constructor parameter, or [In] struct field, of type Lazy[T] does not
+// construct T; instead [Lazy.Get] resolves it on first call and returns the
+// same result afterwards:
+//
+//	func NewReportService(pdf oak.Lazy[*PDFRenderer]) *ReportService
+//
+//	renderer, err := s.pdf.Get()
+//
+// A Lazy edge is not followed when [Container.Build] looks for cycles, which
+// makes it the supported way to break a legitimate cycle between two
+// providers. Build still reports a missing provider for T.
+//
+// A Lazy injected into a scoped or transient instance resolved from a
+// [Scope] resolves T from that scope. Get cannot be called while Build is
+// running, which includes constructors of singletons and functions passed to
+// [Container.Invoke] before Build, and must not be called from inside a
+// constructor.
+type Lazy[T any] struct {
+	v *lazyValue
+}
+
+// Get resolves T on first call and returns the cached result afterwards. It
+// is safe for concurrent use. A failed resolution is not cached, so a later
+// call tries again.
+func (l Lazy[T]) Get() (T, error) {
+	var zero T
+	if l.v == nil {
+		return zero, fmt.Errorf("lazy %s: not injected by a container", reflect.TypeOf((*T)(nil)).Elem())
+	}
+
+	val, err := l.v.get()
+	if err != nil {
+		return zero, err
+	}
+
+	// The value was resolved for T, so the assertion only fails for a nil
+	// interface, which leaves the zero value.
+	out, _ := val.Interface().(T)
+	return out, nil
+}
+
+// lazyType returns the wrapped type, marking Lazy for newDependencies.
+func (Lazy[T]) lazyType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// withValue returns a Lazy backed by v. Its unexported field cannot be set
+// through reflection, so arguments builds Lazy values through this method.
+func (Lazy[T]) withValue(v *lazyValue) reflect.Value {
+	return reflect.ValueOf(Lazy[T]{v: v})
+}
+
+type lazyMarker interface {
+	lazyType() reflect.Type
+	withValue(v *lazyValue) reflect.Value
+}
+
+var lazyMarkerType = reflect.TypeOf((*lazyMarker)(nil)).Elem()
+
+// isLazy reports whether t is an instantiation of [Lazy] and returns the
+// wrapped type.
+func isLazy(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Struct || !t.Implements(lazyMarkerType) {
+		return nil, false
+	}
+
+	// Structs embedding a Lazy implement the marker too; only the generic
+	// type itself has the unexported value as its only field.
+	if t.NumField() != 1 || t.Field(0).Type != reflect.TypeOf((*lazyValue)(nil)) {
+		return nil, false
+	}
+	return reflect.Zero(t).Interface().(lazyMarker).lazyType(), true
+}
+
+// unwrapLazy turns d into a lazy dependency on the wrapped type when it is
+// declared as [Lazy].
+func unwrapLazy(d *dependency) error {
+	inner, ok := isLazy(d.typ)
+	if !ok {
+		return nil
+	}
+	if d.optional {
+		return errors.New("lazy: dependency cannot also be optional")
+	}
+	if isParamStruct(inner) {
+		return errors.New("lazy: parameter structs cannot be wrapped")
+	}
+	if isWrapper(inner) {
+		return fmt.Errorf("lazy: cannot wrap %s", inner)
+	}
+
+	d.wrapper = d.typ
+	d.typ = inner
+	d.lazy = true
+	return nil
+}
+
+// lazyValue resolves a single dependency on first use and caches it.
+type lazyValue struct {
+	resolve func() (reflect.Value, error)
+
+	mu   sync.Mutex
+	done bool
+	val  reflect.Value
+}
+
+func (l *lazyValue) get() (reflect.Value, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.done {
+		return l.val, nil
+	}
+
+	val, err := l.resolve()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	l.val, l.done = val, true
+	return val, nil
+}
+
+// lazyDependency returns the [Lazy] value for d. It resolves d through s when
+// the owner was resolved from a scope, and through the container otherwise.
+func (c *container) lazyDependency(d dependency, s *scope) reflect.Value {
+	v := &lazyValue{resolve: c.deferredResolve("lazy", d, s)}
+	return reflect.Zero(d.wrapper).Interface().(lazyMarker).withValue(v)
+}
+
+// deferredResolve returns a function resolving d on demand, for [Lazy] and
+// [Provider] dependencies. It resolves through s when the owner was resolved
+// from a scope, and through the container otherwise; what prefixes errors.
+func (c *container) deferredResolve(what string, d dependency, s *scope) func() (reflect.Value, error) {
+	var r Resolver = c
+	if s != nil {
+		r = s
+	}
+
+	return func() (reflect.Value, error) {
+		if c.building.Load() {
+			return reflect.Value{}, fmt.Errorf("%s %s: %w: called while Build is running", what, d.typ, ErrNotBuilt)
+		}
+
+		var val reflect.Value
+		var err error
+		if d.name != "" {
+			val, err = r.ResolveNamed(d.name, d.typ)
+		} else {
+			val, err = r.Resolve(d.typ)
+		}
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s %s: %w", what, d.typ, err)
+		}
+		return val, nil
+	}
+}
+
+// lazySingleton caches the instance of a provider registered with
+// [WithLazy].
+type lazySingleton struct {
+	mu   sync.Mutex
+	done bool
+	inst reflect.Value
+
+	// bindings holds the instance decorated through each interface binding
+	// that has decorators.
+	bindings map[reflect.Type]reflect.Value
+}
+
+// lazyInstance returns the instance of the lazy singleton p, constructing it
+// on first use. Concurrent callers wait for a single construction; a failed
+// construction is not cached.
+func (c *container) lazyInstance(p *provider) (reflect.Value, error) {
+	l := p.lazy
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.done {
+		return l.inst, nil
+	}
+
+	if c.shutdown {
+		return reflect.Value{}, fmt.Errorf("%w: cannot construct lazy %s", ErrAlreadyShutdown, p.outType)
+	}
+
+	inst, bindings, cleanup, err := c.newSingleton(p)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	// Dependencies were tracked while they were constructed, so appending
+	// now keeps closers in dependency order.
+	c.track(p, inst, cleanup)
+
+	l.inst, l.bindings, l.done = inst, bindings, true
+	return inst, nil
+}
@@ -0,0 +1,268 @@
+package oak
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type testRenderer struct{ Logger *testLogger }
+
+type testReportService struct {
+	Renderer Lazy[*testRenderer]
+}
+
+// testNode and testPeer form a cycle that is broken by a Lazy edge.
+type testNode struct{ Peer Lazy[*testPeer] }
+type testPeer struct{ Node *testNode }
+
+func TestLazy(t *testing.T) {
+	t.Run("defers construction until Get", func(t *testing.T) {
+		c := New()
+		var calls int
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(log *testLogger) *testRenderer {
+			calls++
+			return &testRenderer{Logger: log}
+		}, WithLifetime(Transient))
+		mustRegister(t, c, func(r Lazy[*testRenderer]) *testReportService {
+			return &testReportService{Renderer: r}
+		})
+		mustBuild(t, c)
+
+		svc, _ := Resolve[*testReportService](c)
+		if calls != 0 {
+			t.Fatalf("expected no construction before Get, got %d", calls)
+		}
+
+		r1, err := svc.Renderer.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		r2, _ := svc.Renderer.Get()
+		if r1 != r2 || calls != 1 {
+			t.Fatalf("expected one cached instance, got %d constructions", calls)
+		}
+	})
+
+	t.Run("singleton target is shared", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustBuild(t, c)
+
+		lazy, err := Invoke[Lazy[*testLogger]](c, func(l Lazy[*testLogger]) Lazy[*testLogger] { return l })
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, _ := lazy.Get()
+		log, _ := Resolve[*testLogger](c)
+		if got != log {
+			t.Fatal("Get should return the container's singleton")
+		}
+	})
+
+	t.Run("breaks a cycle", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func(p Lazy[*testPeer]) *testNode { return &testNode{Peer: p} })
+		mustRegister(t, c, func(n *testNode) *testPeer { return &testPeer{Node: n} })
+		mustBuild(t, c)
+
+		node, _ := Resolve[*testNode](c)
+		peer, err := node.Peer.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if peer.Node != node {
+			t.Fatal("peer should point back to the node")
+		}
+	})
+
+	t.Run("missing provider fails build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func(Lazy[*testRenderer]) *testReportService { return &testReportService{} })
+
+		if err := c.Build(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("Get during build returns ErrNotBuilt", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(log Lazy[*testLogger]) (*testRenderer, error) {
+			_, err := log.Get()
+			return &testRenderer{}, err
+		})
+
+		if err := c.Build(); !errors.Is(err, ErrNotBuilt) {
+			t.Fatalf("expected ErrNotBuilt, got: %v", err)
+		}
+	})
+
+	t.Run("failed resolution is retried", func(t *testing.T) {
+		c := New()
+		var fail atomic.Bool
+		fail.Store(true)
+		mustRegister(t, c, func() (*testRenderer, error) {
+			if fail.Load() {
+				return nil, errors.New("boom")
+			}
+			return &testRenderer{}, nil
+		}, WithLifetime(Transient))
+		mustRegister(t, c, func(r Lazy[*testRenderer]) *testReportService {
+			return &testReportService{Renderer: r}
+		})
+		mustBuild(t, c)
+
+		svc, _ := Resolve[*testReportService](c)
+		if _, err := svc.Renderer.Get(); err == nil {
+			t.Fatal("expected error from the first Get")
+		}
+		fail.Store(false)
+		if r, err := svc.Renderer.Get(); err != nil || r == nil {
+			t.Fatalf("expected a renderer on retry, got %v, %v", r, err)
+		}
+	})
+
+	t.Run("resolves from the owner's scope", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		type holder struct{ UoW Lazy[*testUnitOfWork] }
+		mustRegister(t, c, func(u Lazy[*testUnitOfWork]) *holder {
+			return &holder{UoW: u}
+		}, WithLifetime(Transient))
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		h, err := Resolve[*holder](s)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := h.UoW.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		u, _ := Resolve[*testUnitOfWork](s)
+		if got != u {
+			t.Fatal("Get should resolve from the scope")
+		}
+	})
+
+	t.Run("singleton with scoped target fails build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustRegister(t, c, func(Lazy[*testUnitOfWork]) *testRenderer { return &testRenderer{} })
+
+		if err := c.Build(); !errors.Is(err, ErrLifetimeMismatch) {
+			t.Fatalf("expected ErrLifetimeMismatch, got: %v", err)
+		}
+	})
+
+	t.Run("singleton with scoped target through a transient fails build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustRegister(t, c, func(*testUnitOfWork) *testTracer { return &testTracer{} }, WithLifetime(Transient))
+		mustRegister(t, c, func(Lazy[*testTracer]) *testRenderer { return &testRenderer{} })
+
+		if err := c.Build(); !errors.Is(err, ErrLifetimeMismatch) {
+			t.Fatalf("expected ErrLifetimeMismatch, got: %v", err)
+		}
+	})
+
+	t.Run("named", func(t *testing.T) {
+		c := New()
+		mustRegisterNamed(t, c, "audit", func() *testLogger { return &testLogger{Prefix: "audit"} })
+		mustRegister(t, c, func(log Lazy[*testLogger]) *testRenderer {
+			return &testRenderer{}
+		}, WithParamName(0, "audit"))
+		mustBuild(t, c)
+
+		lazy, err := Invoke[Lazy[*testLogger]](c, func(p struct {
+			In
+			Log Lazy[*testLogger] `oak:"name=audit"`
+		}) Lazy[*testLogger] {
+			return p.Log
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		v, err := lazy.Get()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v.Prefix != "audit" {
+			t.Fatalf("expected audit logger, got %q", v.Prefix)
+		}
+	})
+
+	t.Run("concurrent Get constructs once", func(t *testing.T) {
+		c := New()
+		var calls atomic.Int32
+		mustRegister(t, c, func() *testRenderer {
+			calls.Add(1)
+			return &testRenderer{}
+		}, WithLifetime(Transient))
+		mustRegister(t, c, func(r Lazy[*testRenderer]) *testReportService {
+			return &testReportService{Renderer: r}
+		})
+		mustBuild(t, c)
+
+		svc, _ := Resolve[*testReportService](c)
+
+		const goroutines = 50
+		var wg sync.WaitGroup
+		results := make(chan *testRenderer, goroutines)
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r, err := svc.Renderer.Get()
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				results <- r
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		first := <-results
+		for r := range results {
+			if r != first {
+				t.Fatal("concurrent Get calls should share the instance")
+			}
+		}
+		if n := calls.Load(); n != 1 {
+			t.Fatalf("expected one construction, got %d", n)
+		}
+	})
+
+	t.Run("zero value returns error", func(t *testing.T) {
+		var l Lazy[*testRenderer]
+		if _, err := l.Get(); err == nil {
+			t.Fatal("expected error for a Lazy not injected by a container")
+		}
+	})
+
+	t.Run("rejects invalid wrapping", func(t *testing.T) {
+		type params struct {
+			In
+			Renderer Lazy[*testRenderer] `oak:"optional"`
+		}
+
+		for _, ctor := range []interface{}{
+			func(params) *testReportService { return nil },
+			func(Lazy[Optional[*testRenderer]]) *testReportService { return nil },
+			func(Optional[Lazy[*testRenderer]]) *testReportService { return nil },
+		} {
+			if err := New().Register(ctor); err == nil {
+				t.Fatalf("expected error for %T", ctor)
+			}
+		}
+	})
+}
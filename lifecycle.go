@@ -0,0 +1,207 @@
+package oak
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Starter is implemented by singletons that need to start work, such as
+// listening on a port, once all their dependencies exist.
+// [Container.Start] calls Start in dependency order.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by singletons that need to stop the work started by
+// [Starter]. [Container.Shutdown] calls Stop in reverse start order, before
+// any [io.Closer] is closed.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// Shutdowner is implemented by singletons whose cleanup can honor a
+// deadline, such as [net/http.Server]. [Container.Shutdown] calls Shutdown
+// with its context instead of Close, even if the instance also implements
+// [io.Closer].
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// hook is a lifecycle hook registered with [OnStart] or [OnStop].
+type hook struct {
+	// typ is the instance type the hook was declared for.
+	typ reflect.Type
+	fn  func(ctx context.Context, inst reflect.Value) error
+}
+
+func newHook[T any](fn func(context.Context, T) error) hook {
+	return hook{
+		typ: reflect.TypeOf((*T)(nil)).Elem(),
+		fn: func(ctx context.Context, inst reflect.Value) error {
+			// register checked that the instance is assignable to T, so
+			// the assertion only fails for a nil interface.
+			v, _ := inst.Interface().(T)
+			return fn(ctx, v)
+		},
+	}
+}
+
+// hooks returns every hook registered on p with [OnStart], [OnStop],
+// [WithCleanup] or [WithCleanupContext].
+func (p *provider) hooks() []hook {
+	var hooks []hook
+	hooks = append(hooks, p.onStart...)
+	hooks = append(hooks, p.onStop...)
+	return append(hooks, p.cleanups...)
+}
+
+// component is a constructed singleton and the provider it came from.
+type component struct {
+	p    *provider
+	inst reflect.Value
+}
+
+// start calls Start on the instance if it implements [Starter], then runs
+// the [OnStart] hooks. If a hook fails after Start or an earlier hook
+// succeeded, the component is partly running, so it is stopped before
+// returning and the stop error is joined with the hook's.
+func (comp component) start(ctx context.Context) error {
+	s, ok := comp.inst.Interface().(Starter)
+	if ok {
+		if err := s.Start(ctx); err != nil {
+			return fmt.Errorf("starting %s: %w", comp.p.outType, err)
+		}
+	}
+	for i, h := range comp.p.onStart {
+		if err := h.fn(ctx, comp.inst); err != nil {
+			err = fmt.Errorf("starting %s: %w", comp.p.outType, err)
+			if !ok && i == 0 {
+				return err
+			}
+			if stopErr := comp.stop(ctx); stopErr != nil {
+				return errors.Join(err, stopErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// stop runs the [OnStop] hooks in reverse, then calls Stop on the instance
+// if it implements [Stopper]. Every step runs; their errors are joined.
+func (comp component) stop(ctx context.Context) error {
+	var errs []error
+	for i := len(comp.p.onStop) - 1; i >= 0; i-- {
+		if err := comp.p.onStop[i].fn(ctx, comp.inst); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s, ok := comp.inst.Interface().(Stopper); ok {
+		if err := s.Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("stopping %s: %w", comp.p.outType, err)
+	}
+	return nil
+}
+
+func (c *container) Start(ctx context.Context) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.built {
+		return ErrNotBuilt
+	}
+	if c.shutdown {
+		return ErrAlreadyShutdown
+	}
+
+	c.lifeMu.Lock()
+	defer c.lifeMu.Unlock()
+
+	if c.running {
+		return ErrAlreadyStarted
+	}
+
+	c.trackMu.Lock()
+	components := append([]component(nil), c.constructed...)
+	c.trackMu.Unlock()
+
+	var started []component
+	for _, comp := range components {
+		err := ctx.Err()
+		if err != nil {
+			err = fmt.Errorf("starting %s: skipped: %w", comp.p.outType, err)
+		} else {
+			err = comp.start(ctx)
+		}
+		if err != nil {
+			errs := append([]error{err}, stopComponents(ctx, started)...)
+			return errors.Join(errs...)
+		}
+		started = append(started, comp)
+	}
+
+	c.started = started
+	c.running = true
+	return nil
+}
+
+// stopComponents stops comps in reverse order and returns their errors.
+// Once ctx is done, each remaining component is skipped with an error naming
+// its type.
+func stopComponents(ctx context.Context, comps []component) []error {
+	var errs []error
+	for i := len(comps) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("stopping %s: skipped: %w", comps[i].p.outType, err))
+			continue
+		}
+		if err := comps[i].stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// closer is a cleanup step [Container.Shutdown] runs for a singleton.
+type closer struct {
+	// p is the provider the instance came from.
+	p  *provider
+	fn func(ctx context.Context) error
+}
+
+// newCloser returns the closer for inst, provided by p, if it implements
+// [Shutdowner] or [io.Closer].
+func newCloser(p *provider, inst reflect.Value) (closer, bool) {
+	switch v := inst.Interface().(type) {
+	case Shutdowner:
+		return closer{p: p, fn: v.Shutdown}, true
+	case io.Closer:
+		return closer{p: p, fn: func(context.Context) error { return v.Close() }}, true
+	}
+	return closer{}, false
+}
+
+// runClosers runs closers in reverse order and returns their errors, each
+// naming the type it came from. Once ctx is done, the remaining closers are
+// skipped with an error each.
+func runClosers(ctx context.Context, closers []closer) []error {
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		cl := closers[i]
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("closing %s: skipped: %w", cl.p.outType, err))
+			continue
+		}
+		if err := cl.fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("closing %s: %w", cl.p.outType, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,365 @@
+package oak
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testComponent implements Starter, Stopper and io.Closer, recording each call
+// as "<event>:<name>" in a shared slice.
+type testComponent struct {
+	Name     string
+	Events   *[]string
+	StartErr error
+	StopErr  error
+}
+
+func (s *testComponent) Start(context.Context) error {
+	*s.Events = append(*s.Events, "start:"+s.Name)
+	return s.StartErr
+}
+
+func (s *testComponent) Stop(context.Context) error {
+	*s.Events = append(*s.Events, "stop:"+s.Name)
+	return s.StopErr
+}
+
+func (s *testComponent) Close() error {
+	*s.Events = append(*s.Events, "close:"+s.Name)
+	return nil
+}
+
+// testComponentB and testComponentC are distinct types sharing testComponent's
+// lifecycle methods.
+type testComponentB struct{ testComponent }
+
+type testComponentC struct{ testComponent }
+
+func registerTestServices(t *testing.T, c Container, events *[]string, errB error) {
+	t.Helper()
+	mustRegister(t, c, func() *testComponent {
+		return &testComponent{Name: "a", Events: events}
+	})
+	mustRegister(t, c, func(*testComponent) *testComponentB {
+		return &testComponentB{testComponent{Name: "b", Events: events, StartErr: errB}}
+	})
+	mustRegister(t, c, func(*testComponentB) *testComponentC {
+		return &testComponentC{testComponent{Name: "c", Events: events}}
+	})
+}
+
+func TestStart(t *testing.T) {
+	t.Run("starts in dependency order and stops in reverse", func(t *testing.T) {
+		c := New()
+		var events []string
+		// Registered dependents first, so the order comes from the graph.
+		mustRegister(t, c, func(*testComponentB) *testComponentC {
+			return &testComponentC{testComponent{Name: "c", Events: &events}}
+		})
+		mustRegister(t, c, func(*testComponent) *testComponentB {
+			return &testComponentB{testComponent{Name: "b", Events: &events}}
+		})
+		mustRegister(t, c, func() *testComponent {
+			return &testComponent{Name: "a", Events: &events}
+		})
+		mustBuild(t, c)
+
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+
+		want := "start:a,start:b,start:c,stop:c,stop:b,stop:a,close:c,close:b,close:a"
+		if got := strings.Join(events, ","); got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("runs hooks after Start and before Stop", func(t *testing.T) {
+		c := New()
+		var events []string
+		record := func(event string) func(context.Context, *testLogger) error {
+			return func(context.Context, *testLogger) error {
+				events = append(events, event)
+				return nil
+			}
+		}
+		mustRegister(t, c, newTestLogger,
+			OnStart(record("start1")), OnStart(record("start2")),
+			OnStop(record("stop1")), OnStop(record("stop2")),
+		)
+		mustRegister(t, c, func(*testLogger) *testComponent {
+			return &testComponent{Name: "svc", Events: &events}
+		}, OnStart(func(_ context.Context, s *testComponent) error {
+			events = append(events, "hook:"+s.Name)
+			return nil
+		}), OnStop(func(_ context.Context, s *testComponent) error {
+			events = append(events, "unhook:"+s.Name)
+			return nil
+		}))
+		mustBuild(t, c)
+
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+
+		want := "start1,start2,start:svc,hook:svc,unhook:svc,stop:svc,stop2,stop1,close:svc"
+		if got := strings.Join(events, ","); got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("hook may take an interface", func(t *testing.T) {
+		c := New()
+		var got Starter
+		mustRegister(t, c, func() *testComponent {
+			return &testComponent{Name: "a", Events: new([]string)}
+		}, OnStart(func(_ context.Context, s Starter) error {
+			got = s
+			return nil
+		}))
+		mustBuild(t, c)
+
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		want, _ := Resolve[*testComponent](c)
+		if got != want {
+			t.Fatal("hook should receive the singleton")
+		}
+	})
+
+	t.Run("failure stops started components in reverse", func(t *testing.T) {
+		c := New()
+		var events []string
+		registerTestServices(t, c, &events, errors.New("boom"))
+		mustBuild(t, c)
+
+		err := c.Start(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "starting *oak.testComponentB: boom") {
+			t.Fatalf("expected start error with context, got: %v", err)
+		}
+		if got := strings.Join(events, ","); got != "start:a,start:b,stop:a" {
+			t.Fatalf("expected [start:a start:b stop:a], got %v", events)
+		}
+
+		// Nothing is left running, so Shutdown only closes.
+		events = nil
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+		if got := strings.Join(events, ","); got != "close:c,close:b,close:a" {
+			t.Fatalf("expected only closes, got %v", events)
+		}
+	})
+
+	t.Run("failure joins stop errors", func(t *testing.T) {
+		c := New()
+		var events []string
+		stopErr := errors.New("stop failed")
+		mustRegister(t, c, func() *testComponent {
+			return &testComponent{Name: "a", Events: &events, StopErr: stopErr}
+		})
+		mustRegister(t, c, newTestLogger, OnStart(func(context.Context, *testLogger) error {
+			return errors.New("boom")
+		}))
+		mustBuild(t, c)
+
+		err := c.Start(context.Background())
+		if !errors.Is(err, stopErr) || !strings.Contains(err.Error(), "stopping *oak.testComponent: stop failed") {
+			t.Fatalf("expected joined stop error, got: %v", err)
+		}
+	})
+
+	t.Run("failing hook stops its own started component", func(t *testing.T) {
+		c := New()
+		var events []string
+		mustRegister(t, c, func() *testComponent {
+			return &testComponent{Name: "a", Events: &events}
+		})
+		mustRegister(t, c, func(*testComponent) *testComponentB {
+			return &testComponentB{testComponent{Name: "b", Events: &events}}
+		}, OnStart(func(context.Context, *testComponentB) error {
+			events = append(events, "hook:b")
+			return errors.New("boom")
+		}), OnStop(func(context.Context, *testComponentB) error {
+			events = append(events, "unhook:b")
+			return nil
+		}))
+		mustBuild(t, c)
+
+		err := c.Start(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "starting *oak.testComponentB: boom") {
+			t.Fatalf("expected hook error with context, got: %v", err)
+		}
+		if got := strings.Join(events, ","); got != "start:a,start:b,hook:b,unhook:b,stop:b,stop:a" {
+			t.Fatalf("expected b to be stopped before a, got %v", events)
+		}
+	})
+
+	t.Run("failing hook joins its component's stop error", func(t *testing.T) {
+		c := New()
+		var events []string
+		stopErr := errors.New("stop failed")
+		mustRegister(t, c, func() *testComponent {
+			return &testComponent{Name: "a", Events: &events, StopErr: stopErr}
+		}, OnStart(func(context.Context, *testComponent) error {
+			return errors.New("boom")
+		}))
+		mustBuild(t, c)
+
+		err := c.Start(context.Background())
+		if !errors.Is(err, stopErr) || !strings.Contains(err.Error(), "starting *oak.testComponent: boom") {
+			t.Fatalf("expected start and stop errors, got: %v", err)
+		}
+		if got := strings.Join(events, ","); got != "start:a,stop:a" {
+			t.Fatalf("expected a to be stopped, got %v", events)
+		}
+	})
+
+	t.Run("can be retried after failure", func(t *testing.T) {
+		c := New()
+		fail := true
+		mustRegister(t, c, newTestLogger, OnStart(func(context.Context, *testLogger) error {
+			if fail {
+				return errors.New("boom")
+			}
+			return nil
+		}))
+		mustBuild(t, c)
+
+		if err := c.Start(context.Background()); err == nil {
+			t.Fatal("expected error")
+		}
+		fail = false
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("retry: %v", err)
+		}
+	})
+
+	t.Run("canceled context starts nothing", func(t *testing.T) {
+		c := New()
+		var events []string
+		registerTestServices(t, c, &events, nil)
+		mustBuild(t, c)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := c.Start(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got: %v", err)
+		}
+		if len(events) != 0 {
+			t.Fatalf("expected no events, got %v", events)
+		}
+	})
+
+	t.Run("shutdown deadline skips remaining components", func(t *testing.T) {
+		c := New()
+		var events []string
+		registerTestServices(t, c, &events, nil)
+		mustBuild(t, c)
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := c.Shutdown(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got: %v", err)
+		}
+	})
+
+	t.Run("named singletons start", func(t *testing.T) {
+		c := New()
+		var events []string
+		mustRegisterNamed(t, c, "svc", func() *testComponent {
+			return &testComponent{Name: "svc", Events: &events}
+		}, WithLifetime(Singleton), OnStart(func(context.Context, *testComponent) error {
+			events = append(events, "hook")
+			return nil
+		}))
+		mustBuild(t, c)
+
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		if got := strings.Join(events, ","); got != "start:svc,hook" {
+			t.Fatalf("expected [start:svc hook], got %v", events)
+		}
+	})
+
+	t.Run("lazy singletons start once constructed", func(t *testing.T) {
+		c := New()
+		var events []string
+		mustRegister(t, c, func() *testComponent {
+			return &testComponent{Name: "lazy", Events: &events}
+		}, WithLazy())
+		mustBuild(t, c)
+
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		if len(events) != 0 {
+			t.Fatalf("unconstructed lazy singleton should not start, got %v", events)
+		}
+	})
+
+	t.Run("before build returns ErrNotBuilt", func(t *testing.T) {
+		if err := New().Start(context.Background()); !errors.Is(err, ErrNotBuilt) {
+			t.Fatalf("expected ErrNotBuilt, got: %v", err)
+		}
+	})
+
+	t.Run("twice returns ErrAlreadyStarted", func(t *testing.T) {
+		c := New()
+		mustBuild(t, c)
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		if err := c.Start(context.Background()); !errors.Is(err, ErrAlreadyStarted) {
+			t.Fatalf("expected ErrAlreadyStarted, got: %v", err)
+		}
+	})
+
+	t.Run("after shutdown returns ErrAlreadyShutdown", func(t *testing.T) {
+		c := New()
+		mustBuild(t, c)
+		_ = c.Shutdown(context.Background())
+		if err := c.Start(context.Background()); !errors.Is(err, ErrAlreadyShutdown) {
+			t.Fatalf("expected ErrAlreadyShutdown, got: %v", err)
+		}
+	})
+}
+
+func TestLifecycleHookRegistration(t *testing.T) {
+	hook := OnStart(func(context.Context, *testLogger) error { return nil })
+
+	t.Run("rejects mismatched type", func(t *testing.T) {
+		err := New().Register(newTestConfig, hook)
+		if err == nil || !strings.Contains(err.Error(), "not assignable") {
+			t.Fatalf("expected assignability error, got: %v", err)
+		}
+	})
+
+	t.Run("rejects non-singletons", func(t *testing.T) {
+		for _, lt := range []Lifetime{Transient, Scoped} {
+			if err := New().Register(newTestLogger, WithLifetime(lt), hook); err == nil {
+				t.Fatalf("expected error for %s", lt)
+			}
+		}
+	})
+
+	t.Run("rejects named providers without a singleton lifetime", func(t *testing.T) {
+		err := New().RegisterNamed("audit", newTestLogger, hook)
+		if err == nil || !strings.Contains(err.Error(), "transient provider cannot have hooks") {
+			t.Fatalf("expected lifetime error, got: %v", err)
+		}
+	})
+}
@@ -12,6 +12,11 @@ const (
 	// Transient means a new instance is constructed on every
 	// [Container.Resolve] call.
 	Transient
+
+	// Scoped means one instance is constructed per [Scope] and reused for
+	// every resolution within that scope. Scoped providers can only be
+	// resolved from a scope created with [Container.NewScope].
+	Scoped
 )
 
 // String returns the human-readable name of the lifetime.
@@ -21,6 +26,8 @@ func (l Lifetime) String() string {
 		return "singleton"
 	case Transient:
 		return "transient"
+	case Scoped:
+		return "scoped"
 	default:
 		return "unknown"
 	}
@@ -9,6 +9,7 @@ func TestLifetime_String(t *testing.T) {
 	}{
 		{Singleton, "singleton"},
 		{Transient, "transient"},
+		{Scoped, "scoped"},
 		{Lifetime(99), "unknown"},
 	}
 
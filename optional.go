@@ -0,0 +1,93 @@
+package oak
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Optional wraps a dependency that may have no provider. A constructor
+// parameter, or [In] struct field, of type Optional[T] receives the instance
+// of T with Present set when a provider for T exists, and the zero value
+// otherwise:
+//
+//	func NewServer(cfg *Config, tracer oak.Optional[*Tracer]) *Server {
+//	    if tracer.Present {
+//	        // use tracer.Value
+//	    }
+//	}
+//
+// [Container.Build] does not report a missing provider for T, but validates
+// T's dependencies and detects cycles through it when the provider exists.
+// Optional may be combined with [WithParamName] or a name tag to make a named
+// dependency optional.
+type Optional[T any] struct {
+	// Value is the resolved instance, or the zero value of T.
+	Value T
+
+	// Present reports whether a provider for T was registered.
+	Present bool
+}
+
+// optionalType returns the wrapped type, marking Optional for newDependencies.
+func (Optional[T]) optionalType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+type optionalMarker interface {
+	optionalType() reflect.Type
+}
+
+var optionalMarkerType = reflect.TypeOf((*optionalMarker)(nil)).Elem()
+
+// isOptional reports whether t is an instantiation of [Optional] and returns
+// the wrapped type.
+func isOptional(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Struct || !t.Implements(optionalMarkerType) {
+		return nil, false
+	}
+
+	// Structs embedding an Optional implement the marker too; only the
+	// generic type itself has the wrapped type as its first field.
+	inner := reflect.Zero(t).Interface().(optionalMarker).optionalType()
+	if t.NumField() != 2 || t.Field(0).Type != inner {
+		return nil, false
+	}
+	return inner, true
+}
+
+// unwrapOptional turns d into an optional dependency on the wrapped type when
+// it is declared as [Optional].
+func unwrapOptional(d *dependency) error {
+	inner, ok := isOptional(d.typ)
+	if !ok {
+		return nil
+	}
+	if isParamStruct(inner) {
+		return errors.New("optional: parameter structs cannot be wrapped")
+	}
+	if isWrapper(inner) {
+		return fmt.Errorf("optional: cannot wrap %s", inner)
+	}
+
+	d.wrapper = d.typ
+	d.typ = inner
+	d.optional = true
+	return nil
+}
+
+// optionalValue wraps inst, the value resolved for the [Optional] dependency
+// d, in its declared type.
+func (c *container) optionalValue(d dependency, inst reflect.Value) reflect.Value {
+	var present bool
+	if d.name != "" {
+		_, present = c.named[d.name]
+	} else {
+		_, present = c.providers[d.typ]
+	}
+
+	out := reflect.New(d.wrapper).Elem()
+	out.Field(0).Set(inst)
+	out.Field(1).SetBool(present)
+	return out
+}
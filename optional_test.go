@@ -0,0 +1,160 @@
+package oak
+
+import (
+	"errors"
+	"testing"
+)
+
+type testTracer struct{ Name string }
+
+type testTracedServer struct {
+	Logger *testLogger
+	Tracer Optional[*testTracer]
+}
+
+func newTestTracedServer(log *testLogger, tracer Optional[*testTracer]) *testTracedServer {
+	return &testTracedServer{Logger: log, Tracer: tracer}
+}
+
+func TestOptional(t *testing.T) {
+	t.Run("missing provider leaves zero value", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestTracedServer)
+		mustBuild(t, c)
+
+		srv, err := Resolve[*testTracedServer](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if srv.Tracer.Present || srv.Tracer.Value != nil {
+			t.Fatalf("expected absent tracer, got %+v", srv.Tracer)
+		}
+	})
+
+	t.Run("existing provider is injected", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func() *testTracer { return &testTracer{Name: "otel"} })
+		mustRegister(t, c, newTestTracedServer)
+		mustBuild(t, c)
+
+		srv, _ := Resolve[*testTracedServer](c)
+		tracer, _ := Resolve[*testTracer](c)
+		if !srv.Tracer.Present || srv.Tracer.Value != tracer {
+			t.Fatalf("expected the singleton tracer, got %+v", srv.Tracer)
+		}
+	})
+
+	t.Run("interface binding", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(log *testLogger) *testOrderService {
+			return &testOrderService{Logger: log}
+		}, As[testService]())
+		mustRegister(t, c, func(svc Optional[testService]) *testTracer {
+			return &testTracer{Name: svc.Value.Name()}
+		})
+		mustBuild(t, c)
+
+		tracer, _ := Resolve[*testTracer](c)
+		if tracer.Name != "order" {
+			t.Fatalf("expected order, got %q", tracer.Name)
+		}
+	})
+
+	t.Run("named", func(t *testing.T) {
+		c := New()
+		mustRegisterNamed(t, c, "primary", func() *testConfig { return &testConfig{DSN: "primary"} })
+		mustRegister(t, c, func(primary, replica Optional[*testConfig]) *testDatabase {
+			if replica.Present {
+				return &testDatabase{Config: replica.Value}
+			}
+			return &testDatabase{Config: primary.Value}
+		}, WithParamName(0, "primary"), WithParamName(1, "replica"))
+		mustBuild(t, c)
+
+		db, _ := Resolve[*testDatabase](c)
+		if db.Config == nil || db.Config.DSN != "primary" {
+			t.Fatalf("expected primary config, got %+v", db.Config)
+		}
+	})
+
+	t.Run("parameter struct field", func(t *testing.T) {
+		type params struct {
+			In
+
+			Logger *testLogger
+			Tracer Optional[*testTracer]
+		}
+
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(p params) *testTracedServer {
+			return &testTracedServer{Logger: p.Logger, Tracer: p.Tracer}
+		})
+		mustBuild(t, c)
+
+		srv, err := Resolve[*testTracedServer](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if srv.Logger == nil || srv.Tracer.Present {
+			t.Fatalf("unexpected server: %+v", srv)
+		}
+	})
+
+	t.Run("build validates existing provider", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func(*testConfig) *testTracer { return &testTracer{} })
+		mustRegister(t, c, func(Optional[*testTracer]) *testLogger { return &testLogger{} })
+
+		if err := c.Build(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("build detects cycle through existing provider", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func(Optional[*testLogger]) *testTracer { return &testTracer{} })
+		mustRegister(t, c, func(*testTracer) *testLogger { return &testLogger{} })
+
+		if err := c.Build(); !errors.Is(err, ErrCircularDependency) {
+			t.Fatalf("expected ErrCircularDependency, got: %v", err)
+		}
+	})
+
+	t.Run("constructor error is not swallowed", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() (*testTracer, error) { return nil, errors.New("boom") }, WithLifetime(Transient))
+		mustRegister(t, c, func(Optional[*testTracer]) *testLogger { return &testLogger{} }, WithLifetime(Transient))
+		mustBuild(t, c)
+
+		if _, err := Resolve[*testLogger](c); err == nil {
+			t.Fatal("expected constructor error")
+		}
+	})
+
+	t.Run("rejects parameter struct", func(t *testing.T) {
+		type params struct {
+			In
+			Logger *testLogger
+		}
+
+		c := New()
+		if err := c.Register(func(Optional[params]) *testTracer { return nil }); err == nil {
+			t.Fatal("expected error for optional parameter struct")
+		}
+	})
+
+	t.Run("struct embedding Optional is a plain dependency", func(t *testing.T) {
+		type wrapped struct{ Optional[*testTracer] }
+
+		c := New()
+		mustRegister(t, c, func(wrapped) *testLogger { return &testLogger{} })
+
+		if err := c.Build(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+}
@@ -1,6 +1,10 @@
 package oak
 
-import "reflect"
+import (
+	"context"
+	"errors"
+	"reflect"
+)
 
 // provider holds the metadata for a single registered constructor.
 type provider struct {
@@ -8,15 +12,245 @@ type provider struct {
 	lifetime    Lifetime
 	name        string
 	outType     reflect.Type
+
+	// file and line locate the Register or RegisterNamed call, and
+	// funcName is the constructor's fully qualified name. They identify the
+	// provider in errors and in [ProviderInfo].
+	file     string
+	line     int
+	funcName string
+
+	// interfaces lists the extra types the provider is registered under, as
+	// requested with [As] or [AsType].
+	interfaces []reflect.Type
+
+	// group is the value group the provider contributes to, set with
+	// [InGroup]. Group members are not resolvable by type.
+	group string
+
+	// paramNames and paramGroups map constructor parameter indexes to named
+	// providers and value groups, as set with [WithParamName] and
+	// [WithParamGroup].
+	paramNames  map[int]string
+	paramGroups map[int]string
+
+	// deps describes how each constructor parameter is resolved, in
+	// parameter order. It is computed by register once options are applied.
+	deps []dependency
+
+	// onStart and onStop hold the lifecycle hooks registered with [OnStart]
+	// and [OnStop], in registration order.
+	onStart []hook
+	onStop  []hook
+
+	// cleanups hold the functions registered with [WithCleanup] and
+	// [WithCleanupContext], in registration order.
+	cleanups []hook
+
+	// cleanup reports whether the constructor returns a cleanup function
+	// as its second result.
+	cleanup bool
+
+	// lazy, set by [WithLazy], holds the instance of a singleton that is
+	// constructed on first resolution instead of during Build.
+	lazy *lazySingleton
+
+	// err records the first misused option; register reports it.
+	err error
+}
+
+// keys returns every type the provider is registered under.
+func (p *provider) keys() []reflect.Type {
+	return append([]reflect.Type{p.outType}, p.interfaces...)
+}
+
+// dependency describes how a single constructor parameter, or a single
+// field of an [In] parameter struct, is resolved.
+type dependency struct {
+	// index is the constructor parameter the dependency fills.
+	index int
+
+	// field is the index of the [In] struct field the dependency fills, or
+	// -1 when it fills the parameter itself.
+	field int
+
+	// typ is the parameter or field type. For an [Optional] or [Lazy]
+	// parameter it is the wrapped type.
+	typ reflect.Type
+
+	// wrapper is the [Optional] or [Lazy] type the parameter is declared as,
+	// or nil.
+	wrapper reflect.Type
+
+	// name, when set, resolves the named provider instead of typ.
+	name string
+
+	// group, when set, fills the parameter (a slice) with every member of
+	// the named value group instead of resolving typ directly.
+	group string
+
+	// optional leaves the zero value in place when no provider exists.
+	optional bool
+
+	// lazy defers resolution to [Lazy.Get] or a [Provider] call; Build does
+	// not follow the edge.
+	lazy bool
 }
 
 // Option configures a provider during registration.
 type Option func(*provider)
 
 // WithLifetime sets the [Lifetime] of the provider. The default is
-// [Singleton].
+// [Singleton], or [Transient] for [Container.RegisterNamed].
 func WithLifetime(l Lifetime) Option {
 	return func(p *provider) {
 		p.lifetime = l
 	}
 }
+
+// WithLazy defers the construction of a [Singleton] provider from
+// [Container.Build] to its first resolution:
+//
+//	c.Register(NewSearchIndex, oak.WithLazy())
+//
+// Build still validates the provider's dependencies, and the instance is
+// constructed exactly once even when several goroutines resolve it at the
+// same time. A lazy singleton that an eagerly built singleton depends on is
+// constructed during Build. Lazily built instances that implement [io.Closer]
+// are closed by [Container.Shutdown] in dependency order like any other
+// singleton. WithLazy cannot be used with other lifetimes; named providers
+// need WithLifetime(Singleton) as well.
+func WithLazy() Option {
+	return func(p *provider) {
+		p.lazy = &lazySingleton{}
+	}
+}
+
+// OnStart registers a hook that [Container.Start] runs with the provider's
+// instance, after calling Start on it if it implements [Starter]:
+//
+//	c.Register(NewServer, oak.OnStart(func(ctx context.Context, s *Server) error {
+//	    return s.Listen()
+//	}))
+//
+// The provider's return type must be assignable to T. Hooks run in
+// registration order and only apply to singletons, including named ones
+// registered with WithLifetime(Singleton).
+func OnStart[T any](fn func(ctx context.Context, instance T) error) Option {
+	h := newHook(fn)
+	return func(p *provider) {
+		p.onStart = append(p.onStart, h)
+	}
+}
+
+// OnStop registers a hook that [Container.Shutdown], or a failed
+// [Container.Start], runs with the provider's instance if it was started.
+// Stop hooks run in reverse registration order, before Stop is called on an
+// instance implementing [Stopper]. The same restrictions as [OnStart] apply.
+func OnStop[T any](fn func(ctx context.Context, instance T) error) Option {
+	h := newHook(fn)
+	return func(p *provider) {
+		p.onStop = append(p.onStop, h)
+	}
+}
+
+// WithCleanup registers fn to release the provider's instance during
+// [Container.Shutdown], for types that cannot implement [io.Closer]:
+//
+//	c.Register(NewRedisClient, oak.WithCleanup(func(r *redis.Client) error {
+//	    return r.Quit()
+//	}))
+//
+// Cleanups run in the same dependency order as closers: after those of the
+// instance's dependents and before those of its dependencies. For one
+// instance they run in reverse registration order, before its own Close or
+// Shutdown method. A failed Build runs the cleanups of the singletons it
+// constructed. The same restrictions as [OnStart] apply.
+func WithCleanup[T any](fn func(instance T) error) Option {
+	return WithCleanupContext(func(_ context.Context, instance T) error {
+		return fn(instance)
+	})
+}
+
+// WithCleanupContext is like [WithCleanup], but fn also receives the context
+// passed to [Container.Shutdown], so it can honor the deadline.
+func WithCleanupContext[T any](fn func(ctx context.Context, instance T) error) Option {
+	h := newHook(fn)
+	return func(p *provider) {
+		p.cleanups = append(p.cleanups, h)
+	}
+}
+
+// As additionally registers the provider under the interface type I, so a
+// constructor returning a concrete type can satisfy dependencies on I:
+//
+//	c.Register(NewPostgresUserRepo, oak.As[UserRepository]())
+//
+// The provider stays resolvable by its concrete type, and every binding
+// shares the same instance according to the provider's lifetime. Register
+// returns an error if the return type does not implement I. As may be
+// repeated to bind several interfaces and cannot be used with
+// [Container.RegisterNamed].
+func As[I any]() Option {
+	return AsType(reflect.TypeOf((*I)(nil)).Elem())
+}
+
+// AsType is the non-generic form of [As]. iface must be an interface type.
+func AsType(iface reflect.Type) Option {
+	return func(p *provider) {
+		p.interfaces = append(p.interfaces, iface)
+	}
+}
+
+// InGroup adds the provider to the named value group instead of registering
+// it by type. Any number of providers may join the same group, and a
+// constructor receives all of them as a slice through [WithParamGroup]:
+//
+//	c.Register(NewUsersHandler, oak.InGroup("routes"))
+//	c.Register(NewOrdersHandler, oak.InGroup("routes"))
+//	c.Register(NewRouter, oak.WithParamGroup(0, "routes")) // func([]http.Handler) *Router
+//
+// Members keep their own lifetime and cannot be combined with [As] or
+// [Container.RegisterNamed].
+func InGroup(group string) Option {
+	return func(p *provider) {
+		if group == "" && p.err == nil {
+			p.err = errors.New("group name cannot be empty")
+		}
+		p.group = group
+	}
+}
+
+// WithParamName fills the constructor parameter at index with the named
+// provider instead of the provider for the parameter's type:
+//
+//	// func NewReportService(primary, replica *sql.DB) *ReportService
+//	c.Register(NewReportService,
+//	    oak.WithParamName(0, "primary"),
+//	    oak.WithParamName(1, "replica"),
+//	)
+//
+// The named provider's return type must be assignable to the parameter type.
+// [Container.Build] validates the edge and detects cycles through it. Use a
+// name tag on an [In] struct field for the same effect in parameter structs.
+func WithParamName(index int, name string) Option {
+	return func(p *provider) {
+		if p.paramNames == nil {
+			p.paramNames = make(map[int]string)
+		}
+		p.paramNames[index] = name
+	}
+}
+
+// WithParamGroup fills the constructor parameter at index with every member
+// of the named value group, in registration order. The parameter must be a
+// slice whose element type every member is assignable to; an empty group
+// yields an empty slice. Build validates the dependencies of every member.
+func WithParamGroup(index int, group string) Option {
+	return func(p *provider) {
+		if p.paramGroups == nil {
+			p.paramGroups = make(map[int]string)
+		}
+		p.paramGroups[index] = group
+	}
+}
@@ -0,0 +1,800 @@
+package oak
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAs(t *testing.T) {
+	t.Run("resolves by interface and concrete type", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, As[testService]())
+		mustBuild(t, c)
+
+		svc, err := Resolve[testService](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		concrete, err := Resolve[*testOrderService](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if svc != testService(concrete) {
+			t.Fatal("interface binding should share the singleton instance")
+		}
+	})
+
+	t.Run("satisfies interface dependency", func(t *testing.T) {
+		type consumer struct{ Svc testService }
+
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, As[testService]())
+		mustRegister(t, c, func(s testService) *consumer { return &consumer{Svc: s} })
+		mustBuild(t, c)
+
+		cons, err := Resolve[*consumer](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cons.Svc.Name() != "order" {
+			t.Fatalf("expected 'order', got %q", cons.Svc.Name())
+		}
+	})
+
+	t.Run("singleton constructed once", func(t *testing.T) {
+		callCount := 0
+		c := New()
+		mustRegister(t, c, func() *testOrderService {
+			callCount++
+			return &testOrderService{}
+		}, As[testService]())
+		mustBuild(t, c)
+
+		_, _ = Resolve[testService](c)
+		_, _ = Resolve[*testOrderService](c)
+
+		if callCount != 1 {
+			t.Fatalf("expected 1 call, got %d", callCount)
+		}
+	})
+
+	t.Run("closer tracked once", func(t *testing.T) {
+		var order []string
+		c := New()
+		mustRegister(t, c, func() *testClosable {
+			return &testClosable{Name: "res", Order: &order}
+		}, AsType(reflect.TypeOf((*interface{ Close() error })(nil)).Elem()))
+		mustBuild(t, c)
+
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(order) != 1 {
+			t.Fatalf("expected a single Close, got %v", order)
+		}
+	})
+
+	t.Run("transient binding creates new instances", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, As[testService](), WithLifetime(Transient))
+		mustBuild(t, c)
+
+		s1, _ := Resolve[testService](c)
+		s2, _ := Resolve[testService](c)
+		if s1 == s2 {
+			t.Fatal("transient binding should create different instances")
+		}
+	})
+
+	t.Run("scoped binding shares instance within scope", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, As[testService](), WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		svc, _ := Resolve[testService](s)
+		concrete, _ := Resolve[*testOrderService](s)
+		if svc != testService(concrete) {
+			t.Fatal("scoped binding should share the scope's instance")
+		}
+	})
+
+	t.Run("type not implementing interface is rejected", func(t *testing.T) {
+		c := New()
+		if err := c.Register(newTestLogger, As[testService]()); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("non-interface type is rejected", func(t *testing.T) {
+		c := New()
+		if err := c.Register(newTestLogger, As[*testLogger]()); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("nil type is rejected", func(t *testing.T) {
+		c := New()
+		err := c.Register(newTestLogger, AsType(nil))
+		if err == nil || err.Error() != "as: interface type cannot be nil" {
+			t.Fatalf("expected nil interface error, got: %v", err)
+		}
+	})
+
+	t.Run("interface already provided returns ErrDuplicateProvider", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, As[testService]())
+
+		err := c.Register(newTestUserService, As[testService]())
+		if !errors.Is(err, ErrDuplicateProvider) {
+			t.Fatalf("expected ErrDuplicateProvider, got: %v", err)
+		}
+	})
+
+	t.Run("rejected registration leaves no bindings", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, As[testService]())
+		_ = c.Register(newTestUserService, As[testService]())
+
+		if err := c.Register(newTestUserService); err != nil {
+			t.Fatalf("concrete type should still be free, got: %v", err)
+		}
+	})
+
+	t.Run("cannot be used with named providers", func(t *testing.T) {
+		c := New()
+		if err := c.RegisterNamed("order", newTestOrderService, As[testService]()); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+// testRouter collects every service registered in the "services" group.
+type testRouter struct{ Services []testService }
+
+func newTestRouter(svcs []testService) *testRouter {
+	return &testRouter{Services: svcs}
+}
+
+func TestInGroup(t *testing.T) {
+	t.Run("injects every member in registration order", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUserService, InGroup("services"))
+		mustRegister(t, c, newTestOrderService, InGroup("services"))
+		mustRegister(t, c, newTestRouter, WithParamGroup(0, "services"))
+		mustRegister(t, c, newTestUserRepo)
+		mustRegister(t, c, newTestDatabase)
+		mustRegister(t, c, newTestConfig)
+		mustBuild(t, c)
+
+		r, err := Resolve[*testRouter](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(r.Services) != 2 {
+			t.Fatalf("expected 2 services, got %d", len(r.Services))
+		}
+		if r.Services[0].Name() != "user" || r.Services[1].Name() != "order" {
+			t.Fatalf("unexpected order: %s, %s", r.Services[0].Name(), r.Services[1].Name())
+		}
+	})
+
+	t.Run("empty group injects empty slice", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestRouter, WithParamGroup(0, "services"))
+		mustBuild(t, c)
+
+		r, err := Resolve[*testRouter](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.Services == nil || len(r.Services) != 0 {
+			t.Fatalf("expected empty non-nil slice, got %#v", r.Services)
+		}
+	})
+
+	t.Run("members of the same type are allowed", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() testService { return &testOrderService{} }, InGroup("services"))
+		mustRegister(t, c, func() testService { return &testOrderService{} }, InGroup("services"))
+		mustRegister(t, c, newTestRouter, WithParamGroup(0, "services"))
+		mustBuild(t, c)
+
+		r, _ := Resolve[*testRouter](c)
+		if len(r.Services) != 2 || r.Services[0] == r.Services[1] {
+			t.Fatalf("expected two distinct members, got %v", r.Services)
+		}
+	})
+
+	t.Run("group members are not resolvable by type", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger, InGroup("loggers"))
+		mustBuild(t, c)
+
+		if _, err := Resolve[*testLogger](c); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("singleton members are shared", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestOrderService, InGroup("services"))
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestRouter, WithParamGroup(0, "services"), WithLifetime(Transient))
+		mustBuild(t, c)
+
+		r1, _ := Resolve[*testRouter](c)
+		r2, _ := Resolve[*testRouter](c)
+		if r1 == r2 {
+			t.Fatal("transient router should be rebuilt")
+		}
+		if r1.Services[0] != r2.Services[0] {
+			t.Fatal("singleton member should be shared")
+		}
+	})
+
+	t.Run("missing member dependency returns ErrProviderNotFound", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestOrderService, InGroup("services")) // needs *testLogger
+
+		if err := c.Build(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("cycle through group is detected", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func(r *testRouter) testService {
+			return &testOrderService{}
+		}, InGroup("services"))
+		mustRegister(t, c, newTestRouter, WithParamGroup(0, "services"))
+
+		if err := c.Build(); !errors.Is(err, ErrCircularDependency) {
+			t.Fatalf("expected ErrCircularDependency, got: %v", err)
+		}
+	})
+
+	t.Run("unassignable member is rejected at build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger, InGroup("services"))
+		mustRegister(t, c, newTestRouter, WithParamGroup(0, "services"))
+
+		if err := c.Build(); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("singleton consuming scoped member returns ErrLifetimeMismatch", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, InGroup("services"), WithLifetime(Scoped))
+		mustRegister(t, c, newTestRouter, WithParamGroup(0, "services"))
+
+		if err := c.Build(); !errors.Is(err, ErrLifetimeMismatch) {
+			t.Fatalf("expected ErrLifetimeMismatch, got: %v", err)
+		}
+	})
+
+	t.Run("invalid registrations are rejected", func(t *testing.T) {
+		c := New()
+		if err := c.Register(newTestLogger, InGroup("")); err == nil {
+			t.Fatal("expected error for empty group name")
+		}
+		if err := c.RegisterNamed("log", newTestLogger, InGroup("loggers")); err == nil {
+			t.Fatal("expected error for named group member")
+		}
+		if err := c.Register(newTestOrderService, InGroup("services"), As[testService]()); err == nil {
+			t.Fatal("expected error for As with InGroup")
+		}
+		if err := c.Register(newTestRouter, WithParamGroup(1, "services")); err == nil {
+			t.Fatal("expected error for out-of-range parameter")
+		}
+		if err := c.Register(newTestOrderService, WithParamGroup(0, "services")); err == nil {
+			t.Fatal("expected error for non-slice parameter")
+		}
+		if err := c.Register(newTestRouter, WithParamGroup(0, "")); err == nil {
+			t.Fatal("expected error for empty param group")
+		}
+	})
+}
+
+func TestWithParamName(t *testing.T) {
+	t.Run("injects named providers by position", func(t *testing.T) {
+		c := New()
+		mustRegisterNamed(t, c, "primary", func() *testConfig { return &testConfig{DSN: "primary"} })
+		mustRegisterNamed(t, c, "replica", func() *testConfig { return &testConfig{DSN: "replica"} })
+		mustRegister(t, c, func(primary, replica *testConfig) *testDatabase {
+			return &testDatabase{Config: primary, Logger: &testLogger{Prefix: replica.DSN}}
+		}, WithParamName(0, "primary"), WithParamName(1, "replica"))
+		mustBuild(t, c)
+
+		db, err := Resolve[*testDatabase](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if db.Config.DSN != "primary" || db.Logger.Prefix != "replica" {
+			t.Fatalf("unexpected wiring: %s, %s", db.Config.DSN, db.Logger.Prefix)
+		}
+	})
+
+	t.Run("unnamed parameters resolve by type", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegisterNamed(t, c, "primary", func() *testConfig { return &testConfig{DSN: "primary"} })
+		mustRegister(t, c, newTestDatabase, WithParamName(0, "primary"))
+		mustBuild(t, c)
+
+		db, _ := Resolve[*testDatabase](c)
+		logger, _ := Resolve[*testLogger](c)
+		if db.Config.DSN != "primary" || db.Logger != logger {
+			t.Fatal("unexpected wiring")
+		}
+	})
+
+	t.Run("named provider may satisfy an interface parameter", func(t *testing.T) {
+		type consumer struct{ Svc testService }
+
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegisterNamed(t, c, "orders", newTestOrderService)
+		mustRegister(t, c, func(s testService) *consumer { return &consumer{Svc: s} }, WithParamName(0, "orders"))
+		mustBuild(t, c)
+
+		cons, _ := Resolve[*consumer](c)
+		if cons.Svc.Name() != "order" {
+			t.Fatalf("expected 'order', got %q", cons.Svc.Name())
+		}
+	})
+
+	t.Run("missing named provider returns ErrProviderNotFound", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestDatabase, WithParamName(0, "primary"))
+
+		if err := c.Build(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("unassignable named provider is rejected at build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegisterNamed(t, c, "primary", newTestLogger)
+		mustRegister(t, c, newTestDatabase, WithParamName(0, "primary"))
+
+		if err := c.Build(); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("cycle through named provider is detected", func(t *testing.T) {
+		c := New()
+		mustRegisterNamed(t, c, "a", newTestCircA)
+		mustRegister(t, c, newTestCircB)
+		mustRegister(t, c, newTestCircC, WithParamName(0, "a"))
+
+		if err := c.Build(); !errors.Is(err, ErrCircularDependency) {
+			t.Fatalf("expected ErrCircularDependency, got: %v", err)
+		}
+	})
+
+	t.Run("named providers with scoped deps are resolved from scopes", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger, WithLifetime(Scoped))
+		mustRegisterNamed(t, c, "orders", newTestOrderService)
+		mustRegister(t, c, func(o *testOrderService) *testUserService {
+			return &testUserService{Logger: o.Logger}
+		}, WithParamName(0, "orders"), WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		svc, err := Resolve[*testUserService](s)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		logger, _ := Resolve[*testLogger](s)
+		if svc.Logger != logger {
+			t.Fatal("named dependency should receive the scope's instance")
+		}
+	})
+
+	t.Run("singleton depending on named provider with scoped deps is rejected", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger, WithLifetime(Scoped))
+		mustRegisterNamed(t, c, "orders", newTestOrderService)
+		mustRegister(t, c, func(o *testOrderService) *testUserService {
+			return &testUserService{}
+		}, WithParamName(0, "orders"))
+
+		if err := c.Build(); !errors.Is(err, ErrLifetimeMismatch) {
+			t.Fatalf("expected ErrLifetimeMismatch, got: %v", err)
+		}
+	})
+
+	t.Run("invalid options are rejected", func(t *testing.T) {
+		type params struct {
+			In
+			Config *testConfig
+		}
+
+		c := New()
+		if err := c.Register(newTestDatabase, WithParamName(2, "primary")); err == nil {
+			t.Error("out-of-range parameter: expected error")
+		}
+		if err := c.Register(newTestDatabase, WithParamName(0, "")); err == nil {
+			t.Error("empty name: expected error")
+		}
+		if err := c.Register(newTestRouter, WithParamName(0, "a"), WithParamGroup(0, "b")); err == nil {
+			t.Error("name and group on one parameter: expected error")
+		}
+		if err := c.Register(func(p params) *testDatabase { return nil }, WithParamName(0, "a")); err == nil {
+			t.Error("parameter struct: expected error")
+		}
+	})
+}
+
+func TestWithLazy(t *testing.T) {
+	t.Run("constructs on first resolve", func(t *testing.T) {
+		c := New()
+		var calls int
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(log *testLogger) *testTracer {
+			calls++
+			return &testTracer{}
+		}, WithLazy())
+		mustBuild(t, c)
+
+		if calls != 0 {
+			t.Fatalf("lazy singleton should not be built by Build, got %d calls", calls)
+		}
+
+		t1, err := Resolve[*testTracer](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		t2, _ := Resolve[*testTracer](c)
+		if t1 != t2 || calls != 1 {
+			t.Fatalf("expected one shared instance, got %d calls", calls)
+		}
+	})
+
+	t.Run("build validates dependencies", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func(*testConfig) *testTracer { return &testTracer{} }, WithLazy())
+
+		if err := c.Build(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("build detects cycles", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestCircA, WithLazy())
+		mustRegister(t, c, newTestCircB, WithLazy())
+		mustRegister(t, c, newTestCircC, WithLazy())
+
+		if err := c.Build(); !errors.Is(err, ErrCircularDependency) {
+			t.Fatalf("expected ErrCircularDependency, got: %v", err)
+		}
+	})
+
+	t.Run("eager dependent builds it during build", func(t *testing.T) {
+		c := New()
+		var calls int
+		mustRegister(t, c, func() *testLogger {
+			calls++
+			return &testLogger{}
+		}, WithLazy())
+		mustRegister(t, c, func(log *testLogger) *testOrderService {
+			return &testOrderService{Logger: log}
+		})
+		mustBuild(t, c)
+
+		svc, _ := Resolve[*testOrderService](c)
+		log, _ := Resolve[*testLogger](c)
+		if calls != 1 || svc.Logger != log {
+			t.Fatalf("expected the instance built for the dependent, got %d calls", calls)
+		}
+	})
+
+	t.Run("concurrent resolves construct once", func(t *testing.T) {
+		c := New()
+		var calls atomic.Int32
+		mustRegister(t, c, func() *testTracer {
+			calls.Add(1)
+			return &testTracer{}
+		}, WithLazy())
+		mustBuild(t, c)
+
+		const goroutines = 50
+		var wg sync.WaitGroup
+		results := make(chan *testTracer, goroutines)
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tr, err := Resolve[*testTracer](c)
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				results <- tr
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		first := <-results
+		for tr := range results {
+			if tr != first {
+				t.Fatal("concurrent resolves should share the instance")
+			}
+		}
+		if n := calls.Load(); n != 1 {
+			t.Fatalf("expected one construction, got %d", n)
+		}
+	})
+
+	t.Run("failed construction is retried", func(t *testing.T) {
+		c := New()
+		fail := true
+		mustRegister(t, c, func() (*testTracer, error) {
+			if fail {
+				return nil, errors.New("boom")
+			}
+			return &testTracer{}, nil
+		}, WithLazy())
+		mustBuild(t, c)
+
+		if _, err := Resolve[*testTracer](c); err == nil {
+			t.Fatal("expected constructor error")
+		}
+		fail = false
+		if tr, err := Resolve[*testTracer](c); err != nil || tr == nil {
+			t.Fatalf("expected an instance on retry, got %v, %v", tr, err)
+		}
+	})
+
+	t.Run("shutdown closes in dependency order", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegister(t, c, func() *testClosable {
+			return &testClosable{Name: "db", Order: &order}
+		}, WithLazy())
+		mustRegister(t, c, func(db *testClosable) *testUnitOfWork {
+			return &testUnitOfWork{Name: "repo", Order: &order}
+		}, WithLazy())
+		// Never resolved, so never constructed nor closed.
+		mustRegister(t, c, func() *testFailCloser { return &testFailCloser{} }, WithLazy())
+		mustBuild(t, c)
+
+		if _, err := Resolve[*testUnitOfWork](c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(order) != 2 || order[0] != "repo" || order[1] != "db" {
+			t.Fatalf("expected [repo db], got %v", order)
+		}
+	})
+
+	t.Run("resolve after shutdown returns ErrAlreadyShutdown", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() *testTracer { return &testTracer{} }, WithLazy())
+		mustBuild(t, c)
+		_ = c.Shutdown(context.Background())
+
+		if _, err := Resolve[*testTracer](c); !errors.Is(err, ErrAlreadyShutdown) {
+			t.Fatalf("expected ErrAlreadyShutdown, got: %v", err)
+		}
+	})
+
+	t.Run("interface binding with decorator", func(t *testing.T) {
+		c := New()
+		var calls int
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(log *testLogger) *testOrderService {
+			calls++
+			return &testOrderService{Logger: log}
+		}, As[testService](), WithLazy())
+		if err := c.Decorate(func(s testService) testService {
+			return &testUserService{}
+		}); err != nil {
+			t.Fatalf("Decorate: %v", err)
+		}
+		mustBuild(t, c)
+
+		s1, err := Resolve[testService](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		s2, _ := Resolve[testService](c)
+		if s1.Name() != "user" || s1 != s2 || calls != 1 {
+			t.Fatalf("expected one decorated instance, got %q with %d calls", s1.Name(), calls)
+		}
+	})
+
+	t.Run("result struct fields", func(t *testing.T) {
+		type result struct {
+			Out
+
+			Logger *testLogger
+			Config *testConfig
+		}
+
+		c := New()
+		var calls int
+		mustRegister(t, c, func() result {
+			calls++
+			return result{Logger: &testLogger{}, Config: &testConfig{}}
+		}, WithLazy())
+		mustBuild(t, c)
+
+		if calls != 0 {
+			t.Fatal("lazy result struct should not be built by Build")
+		}
+		log1, _ := Resolve[*testLogger](c)
+		log2, _ := Resolve[*testLogger](c)
+		_, _ = Resolve[*testConfig](c)
+		if calls != 1 || log1 != log2 {
+			t.Fatalf("expected one construction, got %d", calls)
+		}
+	})
+
+	t.Run("rejected for other lifetimes and named providers", func(t *testing.T) {
+		c := New()
+		if err := c.Register(newTestLogger, WithLazy(), WithLifetime(Transient)); err == nil {
+			t.Fatal("expected error for lazy transient")
+		}
+		if err := c.Register(newTestLogger, WithLifetime(Scoped), WithLazy()); err == nil {
+			t.Fatal("expected error for lazy scoped")
+		}
+		if err := c.RegisterNamed("log", newTestLogger, WithLazy()); err == nil {
+			t.Fatal("expected error for lazy named provider")
+		}
+	})
+}
+
+func TestWithCleanup(t *testing.T) {
+	t.Run("runs in dependency order with closers", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegister(t, c, func() *testClosable {
+			return &testClosable{Name: "db", Order: &order}
+		})
+		mustRegister(t, c, func(*testClosable) *testLogger {
+			return &testLogger{Prefix: "log"}
+		}, WithCleanup(func(l *testLogger) error {
+			order = append(order, l.Prefix)
+			return nil
+		}))
+		mustRegister(t, c, func(*testLogger) *testRequestHandler {
+			return &testRequestHandler{Order: &order}
+		})
+		mustBuild(t, c)
+
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+		if got := strings.Join(order, ","); got != "handler,log,db" {
+			t.Fatalf("expected [handler log db], got %v", order)
+		}
+	})
+
+	t.Run("runs in reverse registration order before Close", func(t *testing.T) {
+		c := New()
+		var order []string
+		record := func(name string) func(*testClosable) error {
+			return func(*testClosable) error {
+				order = append(order, name)
+				return nil
+			}
+		}
+		mustRegister(t, c, func() *testClosable {
+			return &testClosable{Name: "close", Order: &order}
+		}, WithCleanup(record("first")), WithCleanup(record("second")))
+		mustBuild(t, c)
+
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+		if got := strings.Join(order, ","); got != "second,first,close" {
+			t.Fatalf("expected [second first close], got %v", order)
+		}
+	})
+
+	t.Run("context variant receives the shutdown context", func(t *testing.T) {
+		c := New()
+		type key struct{}
+		var got interface{}
+		mustRegister(t, c, newTestLogger, WithCleanupContext(func(ctx context.Context, _ *testLogger) error {
+			got = ctx.Value(key{})
+			return nil
+		}))
+		mustBuild(t, c)
+
+		ctx := context.WithValue(context.Background(), key{}, "v")
+		if err := c.Shutdown(ctx); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+		if got != "v" {
+			t.Fatal("cleanup should receive the shutdown context")
+		}
+	})
+
+	t.Run("errors name the type", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger, WithCleanup(func(*testLogger) error {
+			return errors.New("flush failed")
+		}))
+		mustBuild(t, c)
+
+		err := c.Shutdown(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "closing *oak.testLogger: flush failed") {
+			t.Fatalf("expected cleanup error with type, got: %v", err)
+		}
+	})
+
+	t.Run("runs when Build fails", func(t *testing.T) {
+		c := New()
+		cleaned := false
+		mustRegister(t, c, newTestLogger, WithCleanup(func(*testLogger) error {
+			cleaned = true
+			return nil
+		}))
+		mustRegister(t, c, func(*testLogger) (*testConfig, error) {
+			return nil, errors.New("boom")
+		})
+
+		if err := c.Build(); err == nil {
+			t.Fatal("expected error")
+		}
+		if !cleaned {
+			t.Fatal("cleanup should run when Build fails")
+		}
+	})
+
+	t.Run("runs for lazy singletons once constructed", func(t *testing.T) {
+		c := New()
+		calls := 0
+		mustRegister(t, c, newTestLogger, WithLazy(), WithCleanup(func(*testLogger) error {
+			calls++
+			return nil
+		}))
+		mustBuild(t, c)
+		_, _ = Resolve[*testLogger](c)
+
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected one cleanup, got %d", calls)
+		}
+	})
+
+	t.Run("rejected for mismatched types and non-singletons", func(t *testing.T) {
+		cleanup := WithCleanup(func(*testLogger) error { return nil })
+		if err := New().Register(newTestConfig, cleanup); err == nil {
+			t.Fatal("expected error for mismatched type")
+		}
+		if err := New().Register(newTestLogger, WithLifetime(Transient), cleanup); err == nil {
+			t.Fatal("expected error for transient")
+		}
+		if err := New().RegisterNamed("log", newTestLogger, cleanup); err == nil {
+			t.Fatal("expected error for named provider")
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package oak
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// In marks a parameter struct. A constructor parameter whose type is a struct
+// embedding In is not resolved as a whole; instead every exported field is
+// injected individually:
+//
+//	type ServerParams struct {
+//	    oak.In
+//
+//	    Config  *Config
+//	    DB      *sql.DB          `oak:"name=primary"`
+//	    Tracer  *Tracer          `oak:"optional"`
+//	    Routes  []http.Handler   `oak:"group=routes"`
+//	}
+//
+//	func NewServer(p ServerParams) *Server
+//
+// The oak struct tag holds comma-separated options:
+//
+//   - name=N resolves the named provider N instead of the field type.
+//   - group=G fills the field, which must be a slice, with value group G.
+//   - optional leaves the zero value when no provider exists instead of
+//     failing [Container.Build].
+//
+// Every field is an edge in the dependency graph, so missing providers and
+// cycles are reported by Build as for plain parameters. Unexported fields
+// are not allowed.
+type In struct{}
+
+var inMarker = reflect.TypeOf(In{})
+
+// isParamStruct reports whether t is a struct embedding [In].
+func isParamStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type == inMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// newDependencies describes the parameters of the constructor type fnType,
+// expanding [In] parameter structs into one dependency per field. names and
+// groups map parameter indexes to named providers (see [WithParamName]) and
+// value groups (see [WithParamGroup]).
+func newDependencies(fnType reflect.Type, names, groups map[int]string) ([]dependency, error) {
+	for i, name := range names {
+		if i < 0 || i >= fnType.NumIn() {
+			return nil, fmt.Errorf("param name %q: constructor has no parameter %d", name, i)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("param name: empty name for parameter %d", i)
+		}
+		if group, ok := groups[i]; ok {
+			return nil, fmt.Errorf("param name %q: parameter %d is already filled by group %q", name, i, group)
+		}
+	}
+	for i, group := range groups {
+		if i < 0 || i >= fnType.NumIn() {
+			return nil, fmt.Errorf("param group %q: constructor has no parameter %d", group, i)
+		}
+		if group == "" {
+			return nil, fmt.Errorf("param group: empty group name for parameter %d", i)
+		}
+	}
+
+	var deps []dependency
+	for i := 0; i < fnType.NumIn(); i++ {
+		t := fnType.In(i)
+
+		if isParamStruct(t) {
+			if name, ok := names[i]; ok {
+				return nil, fmt.Errorf("param name %q: parameter %d is a parameter struct; use a name tag instead", name, i)
+			}
+			if group, ok := groups[i]; ok {
+				return nil, fmt.Errorf("param group %q: parameter %d is a parameter struct; use a group tag instead", group, i)
+			}
+			fields, err := paramStructDependencies(i, t)
+			if err != nil {
+				return nil, err
+			}
+			deps = append(deps, fields...)
+			continue
+		}
+
+		d := dependency{index: i, field: -1, typ: t, name: names[i]}
+		if group, ok := groups[i]; ok {
+			if t.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("param group %q: parameter %d is %s, not a slice", group, i, t)
+			}
+			d.group = group
+		}
+		if err := unwrapDependency(&d); err != nil {
+			return nil, fmt.Errorf("parameter %d: %w", i, err)
+		}
+		deps = append(deps, d)
+	}
+
+	return deps, nil
+}
+
+// paramStructDependencies returns one dependency per field of the parameter
+// struct t, which fills constructor parameter index.
+func paramStructDependencies(index int, t reflect.Type) ([]dependency, error) {
+	var deps []dependency
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type == inMarker {
+			continue
+		}
+		if !f.IsExported() {
+			return nil, fmt.Errorf("parameter struct %s: field %s must be exported", t, f.Name)
+		}
+
+		d := dependency{index: index, field: i, typ: f.Type}
+		if err := parseParamTag(f.Tag.Get("oak"), &d); err != nil {
+			return nil, fmt.Errorf("parameter struct %s: field %s: %w", t, f.Name, err)
+		}
+		if err := unwrapDependency(&d); err != nil {
+			return nil, fmt.Errorf("parameter struct %s: field %s: %w", t, f.Name, err)
+		}
+		deps = append(deps, d)
+	}
+	return deps, nil
+}
+
+// unwrapDependency resolves d through the wrapped type when it is declared as
+// [Optional], [Lazy] or [Provider].
+func unwrapDependency(d *dependency) error {
+	for _, unwrap := range []func(*dependency) error{unwrapOptional, unwrapLazy, unwrapProvider} {
+		if err := unwrap(d); err != nil || d.wrapper != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isWrapper reports whether t is an [Optional], [Lazy] or [Provider] type,
+// which cannot be nested in one another.
+func isWrapper(t reflect.Type) bool {
+	if _, ok := isOptional(t); ok {
+		return true
+	}
+	if _, ok := isLazy(t); ok {
+		return true
+	}
+	_, ok := isProvider(t)
+	return ok
+}
+
+// parseParamTag applies the options of an oak struct tag to d.
+func parseParamTag(tag string, d *dependency) error {
+	opts, err := parseTag(tag)
+	if err != nil {
+		return err
+	}
+
+	if opts.group != "" && d.typ.Kind() != reflect.Slice {
+		return fmt.Errorf("group %q: %s is not a slice", opts.group, d.typ)
+	}
+
+	d.name = opts.name
+	d.group = opts.group
+	d.optional = opts.optional
+	return nil
+}
+
+// tagOptions holds the options of an oak struct tag.
+type tagOptions struct {
+	name     string
+	group    string
+	optional bool
+}
+
+// parseTag parses the comma-separated options of an oak struct tag, shared by
+// [In] and [Out] structs.
+func parseTag(tag string) (tagOptions, error) {
+	var opts tagOptions
+	if tag == "" {
+		return opts, nil
+	}
+
+	for _, opt := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "name":
+			if value == "" {
+				return opts, fmt.Errorf("empty name in tag %q", tag)
+			}
+			opts.name = value
+		case "group":
+			if value == "" {
+				return opts, fmt.Errorf("empty group in tag %q", tag)
+			}
+			opts.group = value
+		case "optional":
+			opts.optional = true
+		default:
+			return opts, fmt.Errorf("unknown option %q in tag %q", key, tag)
+		}
+	}
+
+	if opts.name != "" && opts.group != "" {
+		return opts, fmt.Errorf("tag %q sets both name and group", tag)
+	}
+	return opts, nil
+}
@@ -0,0 +1,189 @@
+package oak
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testServerParams struct {
+	In
+
+	Config   *testConfig
+	Logger   *testLogger   `oak:"name=special"`
+	Database *testDatabase `oak:"optional"`
+	Services []testService `oak:"group=services"`
+	Missing  *testUserRepo `oak:"name=missing,optional"`
+}
+
+type testServer struct{ Params testServerParams }
+
+func newTestServer(p testServerParams) *testServer { return &testServer{Params: p} }
+
+func TestParamStruct(t *testing.T) {
+	t.Run("fields are injected according to tags", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestConfig)
+		mustRegister(t, c, newTestLogger)
+		mustRegisterNamed(t, c, "special", func() *testLogger { return &testLogger{Prefix: "special"} })
+		mustRegister(t, c, newTestOrderService, InGroup("services"))
+		mustRegister(t, c, newTestServer)
+		mustBuild(t, c)
+
+		srv, err := Resolve[*testServer](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p := srv.Params
+		if p.Config == nil || p.Config.DSN != "postgres://localhost" {
+			t.Fatalf("Config not injected: %+v", p.Config)
+		}
+		if p.Logger == nil || p.Logger.Prefix != "special" {
+			t.Fatalf("named Logger not injected: %+v", p.Logger)
+		}
+		if p.Database != nil {
+			t.Fatal("optional field without provider should be nil")
+		}
+		if len(p.Services) != 1 || p.Services[0].Name() != "order" {
+			t.Fatalf("group not injected: %v", p.Services)
+		}
+		if p.Missing != nil {
+			t.Fatal("optional named field without provider should be nil")
+		}
+	})
+
+	t.Run("optional field is filled when provider exists", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestConfig)
+		mustRegister(t, c, newTestLogger)
+		mustRegisterNamed(t, c, "special", newTestLogger)
+		mustRegister(t, c, newTestDatabase)
+		mustRegister(t, c, newTestServer)
+		mustBuild(t, c)
+
+		srv, _ := Resolve[*testServer](c)
+		db, _ := Resolve[*testDatabase](c)
+		if srv.Params.Database != db {
+			t.Fatal("optional field should receive the singleton")
+		}
+	})
+
+	t.Run("mixed with plain parameters", func(t *testing.T) {
+		type params struct {
+			In
+			Config *testConfig
+		}
+		c := New()
+		mustRegister(t, c, newTestConfig)
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(log *testLogger, p params) *testDatabase {
+			return &testDatabase{Config: p.Config, Logger: log}
+		})
+		mustBuild(t, c)
+
+		db, err := Resolve[*testDatabase](c)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if db.Config == nil || db.Logger == nil {
+			t.Fatal("dependencies not injected")
+		}
+	})
+
+	t.Run("missing field provider returns ErrProviderNotFound", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegisterNamed(t, c, "special", newTestLogger)
+		mustRegister(t, c, newTestServer) // *testConfig is missing
+
+		err := c.Build()
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+		if !strings.Contains(err.Error(), "*oak.testConfig") {
+			t.Fatalf("expected missing type in error, got: %v", err)
+		}
+	})
+
+	t.Run("missing named field returns ErrProviderNotFound", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestConfig)
+		mustRegister(t, c, newTestServer) // "special" is missing
+
+		if err := c.Build(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected ErrProviderNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("cycle through field is detected", func(t *testing.T) {
+		type params struct {
+			In
+			A *testCircA
+		}
+		c := New()
+		mustRegister(t, c, func(p params) *testCircB { return &testCircB{} })
+		mustRegister(t, c, newTestCircA)
+
+		if err := c.Build(); !errors.Is(err, ErrCircularDependency) {
+			t.Fatalf("expected ErrCircularDependency, got: %v", err)
+		}
+	})
+
+	t.Run("cycle through named field is detected", func(t *testing.T) {
+		type params struct {
+			In
+			Logger *testLogger `oak:"name=log"`
+		}
+		c := New()
+		mustRegister(t, c, func(p params) *testConfig { return &testConfig{} })
+		mustRegisterNamed(t, c, "log", func(cfg *testConfig) *testLogger { return &testLogger{} })
+
+		if err := c.Build(); !errors.Is(err, ErrCircularDependency) {
+			t.Fatalf("expected ErrCircularDependency, got: %v", err)
+		}
+	})
+
+	t.Run("invalid parameter structs are rejected", func(t *testing.T) {
+		type unexported struct {
+			In
+			logger *testLogger
+		}
+		type badOption struct {
+			In
+			Logger *testLogger `oak:"lazy"`
+		}
+		type groupNotSlice struct {
+			In
+			Logger *testLogger `oak:"group=loggers"`
+		}
+		type nameAndGroup struct {
+			In
+			Loggers []*testLogger `oak:"name=a,group=b"`
+		}
+		type emptyName struct {
+			In
+			Logger *testLogger `oak:"name="`
+		}
+
+		ctors := map[string]interface{}{
+			"unexported":      func(p unexported) *testConfig { return nil },
+			"bad option":      func(p badOption) *testConfig { return nil },
+			"group not slice": func(p groupNotSlice) *testConfig { return nil },
+			"name and group":  func(p nameAndGroup) *testConfig { return nil },
+			"empty name":      func(p emptyName) *testConfig { return nil },
+		}
+		for name, ctor := range ctors {
+			if err := New().Register(ctor); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+
+		type params struct {
+			In
+			Logger *testLogger
+		}
+		if err := New().Register(func(p params) *testConfig { return nil }, WithParamGroup(0, "g")); err == nil {
+			t.Error("WithParamGroup on parameter struct: expected error")
+		}
+	})
+}
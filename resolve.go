@@ -1,6 +1,7 @@
 package oak
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 )
@@ -17,19 +18,21 @@ func (c *container) Resolve(t reflect.Type) (reflect.Value, error) {
 		return reflect.Value{}, ErrNotBuilt
 	}
 
-	if inst, ok := c.singletons[t]; ok {
-		return inst, nil
-	}
+	return c.resolveType(t, nil)
+}
 
-	p, ok := c.providers[t]
-	if !ok {
-		return reflect.Value{}, fmt.Errorf("%w: %s", ErrProviderNotFound, t)
+func (c *container) ResolveNamed(name string, t reflect.Type) (reflect.Value, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.built {
+		return reflect.Value{}, ErrNotBuilt
 	}
 
-	return c.construct(p)
+	return c.resolveNamed(name, t, nil)
 }
 
-func (c *container) ResolveNamed(name string, t reflect.Type) (reflect.Value, error) {
+func (c *container) ResolveGroup(group string, t reflect.Type) (reflect.Value, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -37,31 +40,38 @@ func (c *container) ResolveNamed(name string, t reflect.Type) (reflect.Value, er
 		return reflect.Value{}, ErrNotBuilt
 	}
 
-	p, ok := c.named[name]
-	if !ok {
-		return reflect.Value{}, fmt.Errorf("%w: named %q", ErrProviderNotFound, name)
-	}
+	return c.resolveGroup(group, t, nil)
+}
 
-	if !p.outType.AssignableTo(t) {
-		return reflect.Value{}, fmt.Errorf("named provider %q returns %s, not assignable to %s", name, p.outType, t)
+func (c *container) NewScope() Scope {
+	return &scope{
+		c:         c,
+		instances: make(map[*provider]reflect.Value),
+		decorated: make(map[reflect.Type]reflect.Value),
 	}
-
-	return c.construct(p)
 }
 
 // ---------------------------------------------------------------------------
 // Generic helpers
 // ---------------------------------------------------------------------------
 
+// Resolver is the read side of the container. Both [Container] and [Scope]
+// implement it, so the generic helpers work with either.
+type Resolver interface {
+	Resolve(t reflect.Type) (reflect.Value, error)
+	ResolveNamed(name string, t reflect.Type) (reflect.Value, error)
+	ResolveGroup(group string, t reflect.Type) (reflect.Value, error)
+}
+
 // Resolve is a generic helper that resolves a typed provider from the
-// container. It is the recommended way to retrieve values:
+// container or a scope. It is the recommended way to retrieve values:
 //
 //	db, err := oak.Resolve[*Database](c)
-func Resolve[T any](c Container) (T, error) {
+func Resolve[T any](r Resolver) (T, error) {
 	var zero T
 	t := reflect.TypeOf((*T)(nil)).Elem()
 
-	val, err := c.Resolve(t)
+	val, err := r.Resolve(t)
 	if err != nil {
 		return zero, err
 	}
@@ -75,14 +85,14 @@ func Resolve[T any](c Container) (T, error) {
 }
 
 // ResolveNamed is a generic helper that resolves a named provider from the
-// container:
+// container or a scope:
 //
 //	db, err := oak.ResolveNamed[*Database](c, "primary")
-func ResolveNamed[T any](c Container, name string) (T, error) {
+func ResolveNamed[T any](r Resolver, name string) (T, error) {
 	var zero T
 	t := reflect.TypeOf((*T)(nil)).Elem()
 
-	val, err := c.ResolveNamed(name, t)
+	val, err := r.ResolveNamed(name, t)
 	if err != nil {
 		return zero, err
 	}
@@ -95,42 +105,228 @@ func ResolveNamed[T any](c Container, name string) (T, error) {
 	return out, nil
 }
 
+// ResolveGroup is a generic helper that resolves every member of a value
+// group as a slice:
+//
+//	routes, err := oak.ResolveGroup[http.Handler](c, "routes")
+func ResolveGroup[T any](r Resolver, group string) ([]T, error) {
+	t := reflect.TypeOf((*[]T)(nil)).Elem()
+
+	val, err := r.ResolveGroup(group, t)
+	if err != nil {
+		return nil, err
+	}
+
+	return val.Interface().([]T), nil
+}
+
 // ---------------------------------------------------------------------------
 // Internal
 // ---------------------------------------------------------------------------
 
+// resolveType returns the instance for t according to its provider's
+// lifetime. s is the scope the resolution happens in, or nil when resolving
+// from the container itself.
+func (c *container) resolveType(t reflect.Type, s *scope) (reflect.Value, error) {
+	p, ok := c.providers[t]
+	if !ok {
+		return reflect.Value{}, &MissingProviderError{Type: t}
+	}
+
+	inst, err := c.instance(p, s)
+	if err != nil || t == p.outType || len(c.decorators[t]) == 0 {
+		return inst, err
+	}
+	return c.decorateBinding(t, p, inst, s)
+}
+
+// resolveGroup returns a slice of type t holding an instance of every member
+// of group, in registration order.
+func (c *container) resolveGroup(group string, t reflect.Type, s *scope) (reflect.Value, error) {
+	if t.Kind() != reflect.Slice {
+		return reflect.Value{}, fmt.Errorf("group %q: %s is not a slice type", group, t)
+	}
+
+	members := c.groups[group]
+	out := reflect.MakeSlice(t, 0, len(members))
+	for _, m := range members {
+		if !m.outType.AssignableTo(t.Elem()) {
+			return reflect.Value{}, fmt.Errorf("group %q: %s is not assignable to %s", group, m.outType, t.Elem())
+		}
+		inst, err := c.instance(m, s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("group %q: %w", group, err)
+		}
+		out = reflect.Append(out, inst)
+	}
+
+	return out, nil
+}
+
+// resolveDependency returns the value for a single constructor parameter or
+// [In] struct field. Optional dependencies without a provider resolve to the
+// zero value of their type.
+func (c *container) resolveDependency(d dependency, s *scope) (reflect.Value, error) {
+	switch {
+	case d.group != "":
+		return c.resolveGroup(d.group, d.typ, s)
+	case d.name != "":
+		if _, ok := c.named[d.name]; !ok && d.optional {
+			return reflect.Zero(d.typ), nil
+		}
+		return c.resolveNamed(d.name, d.typ, s)
+	}
+
+	if _, ok := c.providers[d.typ]; !ok && d.optional {
+		return reflect.Zero(d.typ), nil
+	}
+	return c.resolveType(d.typ, s)
+}
+
+// instance returns an instance of p according to its lifetime: the cached
+// singleton, the scope's instance, or a freshly constructed one.
+func (c *container) instance(p *provider, s *scope) (reflect.Value, error) {
+	if inst, ok := c.singletons[p]; ok {
+		return inst, nil
+	}
+
+	if p.lazy != nil {
+		return c.lazyInstance(p)
+	}
+
+	if p.lifetime == Scoped {
+		if s == nil {
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrScopeRequired, p.outType)
+		}
+		return s.instance(p)
+	}
+
+	// register rejects cleanup functions on transient providers.
+	inst, _, err := c.construct(p, s)
+	return inst, err
+}
+
+// resolveNamed returns the instance of the named provider according to its
+// lifetime, after checking that its return type is assignable to t. s is the
+// active scope, or nil.
+func (c *container) resolveNamed(name string, t reflect.Type, s *scope) (reflect.Value, error) {
+	p, ok := c.named[name]
+	if !ok {
+		return reflect.Value{}, &MissingProviderError{Type: t, Name: name}
+	}
+
+	if !p.outType.AssignableTo(t) {
+		return reflect.Value{}, fmt.Errorf("named provider %q returns %s, not assignable to %s", name, p.outType, t)
+	}
+
+	return c.instance(p, s)
+}
+
 // construct creates a new instance by resolving all dependencies. Singleton
-// deps come from the cache; transient deps are recursively constructed. This
-// method only reads c.singletons and c.providers, so it is safe under a
-// read-lock after Build.
-func (c *container) construct(p provider) (reflect.Value, error) {
-	fnType := p.constructor.Type()
-	args := make([]reflect.Value, fnType.NumIn())
+// deps come from the cache, scoped deps from s, and transient deps are
+// recursively constructed. Decorators registered for the provider's type are
+// applied to the result. The cleanup function returned by the constructor,
+// if any, is returned too; it has already run if construct fails. This
+// method only reads the container's maps, so it is safe under a read-lock
+// after Build.
+func (c *container) construct(p *provider, s *scope) (reflect.Value, func() error, error) {
+	args, err := c.arguments(p.constructor.Type(), p.deps, s)
+	if err != nil {
+		return reflect.Value{}, nil, constructError(p, err)
+	}
+
+	inst, cleanup, err := call(p.constructor, args)
+	if err != nil {
+		return reflect.Value{}, nil, constructError(p, err)
+	}
 
-	for i := 0; i < fnType.NumIn(); i++ {
-		depType := fnType.In(i)
+	if p.name != "" || p.group != "" {
+		return inst, cleanup, nil
+	}
+	if inst, err = c.decorate(p.outType, inst, s); err != nil {
+		return reflect.Value{}, nil, constructError(p, withCleanup(err, cleanup))
+	}
+	return inst, cleanup, nil
+}
 
-		if inst, ok := c.singletons[depType]; ok {
-			args[i] = inst
-			continue
+// arguments resolves deps into the argument list for a function of type
+// fnType. [In] parameter structs are allocated and filled field by field.
+func (c *container) arguments(fnType reflect.Type, deps []dependency, s *scope) ([]reflect.Value, error) {
+	args := make([]reflect.Value, fnType.NumIn())
+
+	for i := range args {
+		if t := fnType.In(i); isParamStruct(t) {
+			args[i] = reflect.New(t).Elem()
 		}
+	}
 
-		depProvider, ok := c.providers[depType]
-		if !ok {
-			return reflect.Value{}, fmt.Errorf("%w: %s", ErrProviderNotFound, depType)
+	for _, d := range deps {
+		if d.lazy {
+			if _, ok := isLazy(d.wrapper); ok {
+				setArgument(args, d, c.lazyDependency(d, s))
+			} else {
+				setArgument(args, d, c.providerDependency(d, s))
+			}
+			continue
 		}
 
-		inst, err := c.construct(depProvider)
+		inst, err := c.resolveDependency(d, s)
 		if err != nil {
-			return reflect.Value{}, fmt.Errorf("resolving %s: %w", depType, err)
+			return nil, fmt.Errorf("resolving %s: %w", d.typ, err)
 		}
-		args[i] = inst
+		if d.wrapper != nil {
+			inst = c.optionalValue(d, inst)
+		}
+		setArgument(args, d, inst)
 	}
 
-	results := p.constructor.Call(args)
-	if len(results) == 2 && !results[1].IsNil() {
-		return reflect.Value{}, results[1].Interface().(error)
+	return args, nil
+}
+
+// setArgument stores the value for d in args, inside its [In] struct when d
+// is a field.
+func setArgument(args []reflect.Value, d dependency, v reflect.Value) {
+	if d.field < 0 {
+		args[d.index] = v
+	} else {
+		args[d.index].Field(d.field).Set(v)
 	}
+}
+
+// call invokes fn, which returns (T), (T, error) or, for a constructor,
+// (T, cleanup, error), and splits its results. The cleanup is nil unless fn
+// returned one and no error.
+func call(fn reflect.Value, args []reflect.Value) (reflect.Value, func() error, error) {
+	results := fn.Call(args)
+	if last := results[len(results)-1]; len(results) > 1 && !last.IsNil() {
+		return reflect.Value{}, nil, last.Interface().(error)
+	}
+
+	if len(results) == 3 && !results[1].IsNil() {
+		return results[0], cleanupFunc(results[1]), nil
+	}
+	return results[0], nil, nil
+}
 
-	return results[0], nil
+// cleanupFunc adapts fn, a func() or func() error, to func() error.
+func cleanupFunc(fn reflect.Value) func() error {
+	return func() error {
+		out := fn.Call(nil)
+		if len(out) == 1 && !out[0].IsNil() {
+			return out[0].Interface().(error)
+		}
+		return nil
+	}
+}
+
+// withCleanup runs cleanup, if not nil, for an instance that failed after
+// its constructor returned, and joins its error with err.
+func withCleanup(err error, cleanup func() error) error {
+	if cleanup == nil {
+		return err
+	}
+	if cerr := cleanup(); cerr != nil {
+		return errors.Join(err, cerr)
+	}
+	return err
 }
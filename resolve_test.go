@@ -1,6 +1,7 @@
 package oak
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -354,6 +355,103 @@ func TestResolveNamed(t *testing.T) {
 			t.Fatal("named providers should share singleton dependencies")
 		}
 	})
+	t.Run("named singleton is built by Build and cached per name", func(t *testing.T) {
+		c := New()
+		calls := 0
+		ctor := func() *testLogger {
+			calls++
+			return &testLogger{}
+		}
+		mustRegisterNamed(t, c, "a", ctor, WithLifetime(Singleton))
+		mustRegisterNamed(t, c, "b", ctor, WithLifetime(Singleton))
+		mustBuild(t, c)
+
+		if calls != 2 {
+			t.Fatalf("expected Build to construct both, got %d calls", calls)
+		}
+		a1, _ := ResolveNamed[*testLogger](c, "a")
+		a2, _ := ResolveNamed[*testLogger](c, "a")
+		b, _ := ResolveNamed[*testLogger](c, "b")
+		if a1 != a2 || a1 == b || calls != 2 {
+			t.Fatal("named singletons should be cached per name")
+		}
+	})
+
+	t.Run("named singleton is shared with dependents", func(t *testing.T) {
+		c := New()
+		mustRegisterNamed(t, c, "audit", newTestLogger, WithLifetime(Singleton))
+		mustRegister(t, c, newTestOrderService, WithParamName(0, "audit"))
+		mustBuild(t, c)
+
+		log, _ := ResolveNamed[*testLogger](c, "audit")
+		svc, _ := Resolve[*testOrderService](c)
+		if svc.Logger != log {
+			t.Fatal("dependent should receive the cached named singleton")
+		}
+	})
+
+	t.Run("named singleton error fails Build with the name", func(t *testing.T) {
+		c := New()
+		mustRegisterNamed(t, c, "cfg", func() (*testConfig, error) {
+			return nil, errors.New("init failed")
+		}, WithLifetime(Singleton))
+
+		err := c.Build()
+		if err == nil || !strings.Contains(err.Error(), `constructing named provider "cfg" (`) || !strings.HasSuffix(err.Error(), "): init failed") {
+			t.Fatalf("expected named constructor error, got: %v", err)
+		}
+	})
+
+	t.Run("named singleton is closed by Shutdown", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegisterNamed(t, c, "primary", func() *testClosable {
+			return &testClosable{Name: "primary", Order: &order}
+		}, WithLifetime(Singleton))
+		mustRegisterNamed(t, c, "replica", func() *testClosable {
+			return &testClosable{Name: "replica", Order: &order}
+		}, WithLifetime(Singleton))
+		mustBuild(t, c)
+
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+		if got := strings.Join(order, ","); got != "replica,primary" {
+			t.Fatalf("expected [replica primary], got %v", order)
+		}
+	})
+
+	t.Run("named scoped is cached per scope", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegisterNamed(t, c, "uow", newTestUnitOfWork, WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		if _, err := c.ResolveNamed("uow", reflect.TypeOf((*testUnitOfWork)(nil))); !errors.Is(err, ErrScopeRequired) {
+			t.Fatalf("expected ErrScopeRequired, got: %v", err)
+		}
+
+		s1, s2 := c.NewScope(), c.NewScope()
+		u1, _ := ResolveNamed[*testUnitOfWork](s1, "uow")
+		u2, _ := ResolveNamed[*testUnitOfWork](s1, "uow")
+		u3, _ := ResolveNamed[*testUnitOfWork](s2, "uow")
+		if u1 != u2 || u1 == u3 {
+			t.Fatal("named scoped provider should be cached per scope")
+		}
+	})
+
+	t.Run("named singleton depending on scoped fails Build", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustRegisterNamed(t, c, "handler", func(*testUnitOfWork) *testRequestHandler {
+			return &testRequestHandler{}
+		}, WithLifetime(Singleton))
+
+		if err := c.Build(); !errors.Is(err, ErrLifetimeMismatch) {
+			t.Fatalf("expected ErrLifetimeMismatch, got: %v", err)
+		}
+	})
 }
 
 func TestResolveNamedGeneric(t *testing.T) {
@@ -370,6 +468,88 @@ func TestResolveNamedGeneric(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// ResolveGroup
+// ---------------------------------------------------------------------------
+
+func TestResolveGroup(t *testing.T) {
+	t.Run("before build returns ErrNotBuilt", func(t *testing.T) {
+		c := New()
+		if _, err := ResolveGroup[testService](c, "services"); !errors.Is(err, ErrNotBuilt) {
+			t.Fatalf("expected ErrNotBuilt, got: %v", err)
+		}
+	})
+
+	t.Run("returns members", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, InGroup("services"))
+		mustRegister(t, c, func() testService { return &testUserService{} }, InGroup("services"))
+		mustBuild(t, c)
+
+		svcs, err := ResolveGroup[testService](c, "services")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(svcs) != 2 || svcs[0].Name() != "order" || svcs[1].Name() != "user" {
+			t.Fatalf("unexpected members: %v", svcs)
+		}
+	})
+
+	t.Run("unknown group is empty", func(t *testing.T) {
+		c := New()
+		mustBuild(t, c)
+
+		svcs, err := ResolveGroup[testService](c, "missing")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(svcs) != 0 {
+			t.Fatalf("expected no members, got %v", svcs)
+		}
+	})
+
+	t.Run("non-slice type returns error", func(t *testing.T) {
+		c := New()
+		mustBuild(t, c)
+
+		if _, err := c.ResolveGroup("services", reflect.TypeOf((*testLogger)(nil))); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("unassignable element type returns error", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger, InGroup("loggers"))
+		mustBuild(t, c)
+
+		if _, err := ResolveGroup[testService](c, "loggers"); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("scoped members come from the scope", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestOrderService, InGroup("services"), WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		if _, err := ResolveGroup[testService](c, "services"); !errors.Is(err, ErrScopeRequired) {
+			t.Fatalf("expected ErrScopeRequired, got: %v", err)
+		}
+
+		s := c.NewScope()
+		a, err := ResolveGroup[testService](s, "services")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b, _ := ResolveGroup[testService](s, "services")
+		if a[0] != b[0] {
+			t.Fatal("scoped member should be shared within the scope")
+		}
+	})
+}
+
 // ---------------------------------------------------------------------------
 // Concurrency
 // ---------------------------------------------------------------------------
@@ -0,0 +1,109 @@
+package oak
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Out marks a result struct. A constructor returning a struct that embeds Out
+// registers every exported field as its own provider, so one constructor can
+// produce several dependencies:
+//
+//	type DBResult struct {
+//	    oak.Out
+//
+//	    DB       *sql.DB
+//	    Migrator *Migrator
+//	    Replica  *sql.DB        `oak:"name=replica"`
+//	    Check    HealthCheck    `oak:"group=health"`
+//	}
+//
+//	func NewDB(cfg *Config) (DBResult, error)
+//
+// The oak struct tag accepts name=N to register the field as a named provider
+// and group=G to add it to value group G. The constructor runs once per
+// lifetime — once in total for [Singleton], once per [Scope] for [Scoped] —
+// and every field shares the lifetime it was registered with. Unexported
+// fields are not allowed, and the result struct cannot be combined with
+// [As], [InGroup] or [Container.RegisterNamed].
+type Out struct{}
+
+var outMarker = reflect.TypeOf(Out{})
+
+// isResultStruct reports whether t is a struct embedding [Out].
+func isResultStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type == outMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// resultProviders returns one provider per exported field of the result
+// struct returned by p. Each field provider depends on the result struct
+// itself, which p provides, and reads its field from it.
+func resultProviders(p *provider) ([]*provider, error) {
+	if p.name != "" {
+		return nil, fmt.Errorf("named provider %q: result struct %s cannot be named; use name tags on its fields", p.name, p.outType)
+	}
+	if p.group != "" {
+		return nil, fmt.Errorf("group %q: result struct %s cannot join a group; use group tags on its fields", p.group, p.outType)
+	}
+	if len(p.interfaces) > 0 {
+		return nil, fmt.Errorf("as: result struct %s cannot be bound to interfaces", p.outType)
+	}
+
+	t := p.outType
+	var fields []*provider
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type == outMarker {
+			continue
+		}
+		if !f.IsExported() {
+			return nil, fmt.Errorf("result struct %s: field %s must be exported", t, f.Name)
+		}
+
+		opts, err := parseTag(f.Tag.Get("oak"))
+		if err != nil {
+			return nil, fmt.Errorf("result struct %s: field %s: %w", t, f.Name, err)
+		}
+		if opts.optional {
+			return nil, fmt.Errorf("result struct %s: field %s: optional is not valid on result fields", t, f.Name)
+		}
+
+		fp := &provider{
+			constructor: fieldGetter(t, i),
+			lifetime:    p.lifetime,
+			name:        opts.name,
+			group:       opts.group,
+			outType:     f.Type,
+			file:        p.file,
+			line:        p.line,
+			funcName:    p.funcName,
+			deps:        []dependency{{index: 0, field: -1, typ: t}},
+		}
+		if p.lazy != nil {
+			fp.lazy = &lazySingleton{}
+		}
+		fields = append(fields, fp)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("result struct %s has no fields", t)
+	}
+	return fields, nil
+}
+
+// fieldGetter returns a constructor func(T) F that reads field i of struct T.
+func fieldGetter(t reflect.Type, i int) reflect.Value {
+	fnType := reflect.FuncOf([]reflect.Type{t}, []reflect.Type{t.Field(i).Type}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		return []reflect.Value{args[0].Field(i)}
+	})
+}
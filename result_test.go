@@ -0,0 +1,185 @@
+package oak
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type testStorage struct {
+	Out
+
+	Database *testDatabase
+	Primary  *testConfig `oak:"name=primary"`
+	Service  testService `oak:"group=services"`
+	Closable *testClosable
+}
+
+func TestResultStruct(t *testing.T) {
+	t.Run("fields are registered as providers", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(log *testLogger) testStorage {
+			return testStorage{
+				Database: &testDatabase{Logger: log},
+				Primary:  &testConfig{DSN: "primary"},
+				Service:  &testOrderService{Logger: log},
+				Closable: &testClosable{Name: "storage"},
+			}
+		})
+		mustRegister(t, c, newTestRouter, WithParamGroup(0, "services"))
+		mustBuild(t, c)
+
+		db, err := Resolve[*testDatabase](c)
+		if err != nil || db.Logger == nil {
+			t.Fatalf("Database not resolved: %v", err)
+		}
+		cfg, err := ResolveNamed[*testConfig](c, "primary")
+		if err != nil || cfg.DSN != "primary" {
+			t.Fatalf("named field not resolved: %v", err)
+		}
+		r, _ := Resolve[*testRouter](c)
+		if len(r.Services) != 1 || r.Services[0].Name() != "order" {
+			t.Fatalf("group field not injected: %v", r.Services)
+		}
+		if _, err := Resolve[*testConfig](c); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("named field should not be resolvable by type, got: %v", err)
+		}
+	})
+
+	t.Run("singleton constructor runs once", func(t *testing.T) {
+		callCount := 0
+		c := New()
+		mustRegister(t, c, func() testStorage {
+			callCount++
+			return testStorage{
+				Database: &testDatabase{},
+				Primary:  &testConfig{},
+				Service:  &testOrderService{},
+				Closable: &testClosable{},
+			}
+		})
+		mustBuild(t, c)
+
+		_, _ = Resolve[*testDatabase](c)
+		_, _ = Resolve[*testClosable](c)
+		_, _ = ResolveNamed[*testConfig](c, "primary")
+
+		if callCount != 1 {
+			t.Fatalf("expected 1 call, got %d", callCount)
+		}
+	})
+
+	t.Run("fields share the transient lifetime", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() testStorage {
+			return testStorage{Database: &testDatabase{}, Closable: &testClosable{}}
+		}, WithLifetime(Transient))
+		mustBuild(t, c)
+
+		d1, _ := Resolve[*testDatabase](c)
+		d2, _ := Resolve[*testDatabase](c)
+		if d1 == d2 {
+			t.Fatal("transient field should be rebuilt")
+		}
+	})
+
+	t.Run("fields share the scoped lifetime", func(t *testing.T) {
+		callCount := 0
+		c := New()
+		mustRegister(t, c, func() testStorage {
+			callCount++
+			return testStorage{Database: &testDatabase{}, Closable: &testClosable{}}
+		}, WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		_, _ = Resolve[*testDatabase](s)
+		_, _ = Resolve[*testClosable](s)
+		if callCount != 1 {
+			t.Fatalf("expected 1 call per scope, got %d", callCount)
+		}
+		if _, err := Resolve[*testDatabase](c); !errors.Is(err, ErrScopeRequired) {
+			t.Fatalf("expected ErrScopeRequired, got: %v", err)
+		}
+	})
+
+	t.Run("closer fields are closed on shutdown", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() testStorage {
+			return testStorage{Database: &testDatabase{}, Closable: &testClosable{Name: "storage"}}
+		})
+		mustBuild(t, c)
+
+		closable, _ := Resolve[*testClosable](c)
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !closable.Closed {
+			t.Fatal("closer field should be closed")
+		}
+	})
+
+	t.Run("constructor error propagates", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() (testStorage, error) {
+			return testStorage{}, errors.New("storage failed")
+		})
+
+		if err := c.Build(); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("duplicate field type returns ErrDuplicateProvider and registers nothing", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() *testClosable { return &testClosable{} })
+
+		err := c.Register(func() testStorage { return testStorage{} })
+		if !errors.Is(err, ErrDuplicateProvider) {
+			t.Fatalf("expected ErrDuplicateProvider, got: %v", err)
+		}
+		if err := c.Register(func() *testDatabase { return nil }); err != nil {
+			t.Fatalf("rejected result struct should not register fields, got: %v", err)
+		}
+	})
+
+	t.Run("invalid result structs are rejected", func(t *testing.T) {
+		type unexported struct {
+			Out
+			db *testDatabase
+		}
+		type optional struct {
+			Out
+			DB *testDatabase `oak:"optional"`
+		}
+		type empty struct {
+			Out
+		}
+		type duplicate struct {
+			Out
+			A *testDatabase
+			B *testDatabase
+		}
+
+		ctors := map[string]interface{}{
+			"unexported": func() unexported { return unexported{} },
+			"optional":   func() optional { return optional{} },
+			"empty":      func() empty { return empty{} },
+			"duplicate":  func() duplicate { return duplicate{} },
+		}
+		for name, ctor := range ctors {
+			if err := New().Register(ctor); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+
+		ctor := func() testStorage { return testStorage{} }
+		if err := New().RegisterNamed("storage", ctor); err == nil {
+			t.Error("named: expected error")
+		}
+		if err := New().Register(ctor, InGroup("storage")); err == nil {
+			t.Error("group: expected error")
+		}
+	})
+}
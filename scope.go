@@ -0,0 +1,155 @@
+package oak
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Scope is a short-lived child of a [Container], typically created once per
+// request or unit of work. [Scoped] providers are constructed at most once
+// per scope; [Singleton] instances come from the parent container and
+// [Transient] providers behave as usual.
+//
+// A Scope is safe for concurrent use. Call [Scope.Close] when the unit of
+// work is done to release the scoped instances that implement [io.Closer].
+type Scope interface {
+	// Resolve returns the value for the given type. Scoped instances are
+	// cached for the lifetime of the scope. Prefer the generic [Resolve]
+	// helper over calling this method directly.
+	Resolve(t reflect.Type) (reflect.Value, error)
+
+	// ResolveNamed returns the value for the named provider. Scoped
+	// dependencies of the named provider are taken from this scope. Prefer
+	// the generic [ResolveNamed] helper over calling this method directly.
+	ResolveNamed(name string, t reflect.Type) (reflect.Value, error)
+
+	// ResolveGroup returns every member of the value group as a slice of
+	// type t. Scoped members are taken from this scope. Prefer the generic
+	// [ResolveGroup] helper over calling this method directly.
+	ResolveGroup(group string, t reflect.Type) (reflect.Value, error)
+
+	// Close closes every scoped instance that implements [io.Closer], and
+	// runs the cleanup functions returned by scoped constructors, in reverse
+	// dependency order, and joins any errors they return. After
+	// Close the scope can no longer be used; further calls return
+	// [ErrScopeClosed].
+	Close() error
+}
+
+type scope struct {
+	c *container
+
+	mu        sync.Mutex
+	instances map[*provider]reflect.Value
+	decorated map[reflect.Type]reflect.Value
+
+	// closers holds the Close methods of scoped instances that implement
+	// io.Closer, and the cleanup functions their constructors returned, in
+	// the order they were constructed. Close calls them in reverse.
+	closers []func() error
+
+	closed bool
+}
+
+func (s *scope) Resolve(t reflect.Type) (reflect.Value, error) {
+	s.c.mu.RLock()
+	defer s.c.mu.RUnlock()
+
+	if !s.c.built {
+		return reflect.Value{}, ErrNotBuilt
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return reflect.Value{}, ErrScopeClosed
+	}
+
+	return s.c.resolveType(t, s)
+}
+
+func (s *scope) ResolveNamed(name string, t reflect.Type) (reflect.Value, error) {
+	s.c.mu.RLock()
+	defer s.c.mu.RUnlock()
+
+	if !s.c.built {
+		return reflect.Value{}, ErrNotBuilt
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return reflect.Value{}, ErrScopeClosed
+	}
+
+	return s.c.resolveNamed(name, t, s)
+}
+
+func (s *scope) ResolveGroup(group string, t reflect.Type) (reflect.Value, error) {
+	s.c.mu.RLock()
+	defer s.c.mu.RUnlock()
+
+	if !s.c.built {
+		return reflect.Value{}, ErrNotBuilt
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return reflect.Value{}, ErrScopeClosed
+	}
+
+	return s.c.resolveGroup(group, t, s)
+}
+
+func (s *scope) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrScopeClosed
+	}
+
+	s.closed = true
+
+	var errs []error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		if err := s.closers[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.closers = nil
+	s.instances = nil
+	s.decorated = nil
+
+	return errors.Join(errs...)
+}
+
+// instance returns the scoped instance for p, constructing and caching it on
+// first use. The caller must hold s.mu.
+func (s *scope) instance(p *provider) (reflect.Value, error) {
+	if inst, ok := s.instances[p]; ok {
+		return inst, nil
+	}
+
+	inst, cleanup, err := s.c.construct(p, s)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	s.instances[p] = inst
+
+	if cleanup != nil {
+		s.closers = append(s.closers, cleanup)
+	}
+	if closer, ok := inst.Interface().(io.Closer); ok {
+		s.closers = append(s.closers, closer.Close)
+	}
+
+	return inst, nil
+}
@@ -0,0 +1,300 @@
+package oak
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testUnitOfWork is a scoped, closable dependency for scope tests.
+type testUnitOfWork struct {
+	Logger *testLogger
+	Closed bool
+	Order  *[]string
+	Name   string
+}
+
+func (u *testUnitOfWork) Close() error {
+	u.Closed = true
+	if u.Order != nil {
+		*u.Order = append(*u.Order, u.Name)
+	}
+	return nil
+}
+
+type testRequestHandler struct {
+	UoW    *testUnitOfWork
+	Closed bool
+	Order  *[]string
+}
+
+func (h *testRequestHandler) Close() error {
+	h.Closed = true
+	if h.Order != nil {
+		*h.Order = append(*h.Order, "handler")
+	}
+	return nil
+}
+
+func newTestUnitOfWork(log *testLogger) *testUnitOfWork {
+	return &testUnitOfWork{Logger: log}
+}
+
+func TestScope_Resolve(t *testing.T) {
+	t.Run("scoped instance shared within a scope", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		u1, err := Resolve[*testUnitOfWork](s)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		u2, _ := Resolve[*testUnitOfWork](s)
+
+		if u1 != u2 {
+			t.Fatal("scoped provider should return the same instance within a scope")
+		}
+	})
+
+	t.Run("different scopes get different instances", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		u1, _ := Resolve[*testUnitOfWork](c.NewScope())
+		u2, _ := Resolve[*testUnitOfWork](c.NewScope())
+
+		if u1 == u2 {
+			t.Fatal("scopes should not share scoped instances")
+		}
+	})
+
+	t.Run("singletons come from the container", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		logger, _ := Resolve[*testLogger](c)
+		u, _ := Resolve[*testUnitOfWork](c.NewScope())
+		fromScope, _ := Resolve[*testLogger](c.NewScope())
+
+		if u.Logger != logger || fromScope != logger {
+			t.Fatal("scope should share the container's singletons")
+		}
+	})
+
+	t.Run("transient in scope receives scoped dependency", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustRegister(t, c, func(u *testUnitOfWork) *testRequestHandler {
+			return &testRequestHandler{UoW: u}
+		}, WithLifetime(Transient))
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		h1, err := Resolve[*testRequestHandler](s)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		h2, _ := Resolve[*testRequestHandler](s)
+
+		if h1 == h2 {
+			t.Fatal("transient should create different instances")
+		}
+		if h1.UoW != h2.UoW {
+			t.Fatal("transients in one scope should share the scoped dependency")
+		}
+	})
+
+	t.Run("named provider in scope receives scoped dependency", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustRegisterNamed(t, c, "handler", func(u *testUnitOfWork) *testRequestHandler {
+			return &testRequestHandler{UoW: u}
+		})
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		h, err := ResolveNamed[*testRequestHandler](s, "handler")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		u, _ := Resolve[*testUnitOfWork](s)
+
+		if h.UoW != u {
+			t.Fatal("named provider should receive the scope's instance")
+		}
+	})
+
+	t.Run("scoped from container returns ErrScopeRequired", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		_, err := Resolve[*testUnitOfWork](c)
+		if !errors.Is(err, ErrScopeRequired) {
+			t.Fatalf("expected ErrScopeRequired, got: %v", err)
+		}
+	})
+
+	t.Run("transient with scoped dep from container returns ErrScopeRequired", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+		mustRegister(t, c, func(u *testUnitOfWork) *testRequestHandler {
+			return &testRequestHandler{UoW: u}
+		}, WithLifetime(Transient))
+		mustBuild(t, c)
+
+		_, err := Resolve[*testRequestHandler](c)
+		if !errors.Is(err, ErrScopeRequired) {
+			t.Fatalf("expected ErrScopeRequired, got: %v", err)
+		}
+	})
+
+	t.Run("before build returns ErrNotBuilt", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+
+		_, err := c.NewScope().Resolve(reflect.TypeOf((*testLogger)(nil)))
+		if !errors.Is(err, ErrNotBuilt) {
+			t.Fatalf("expected ErrNotBuilt, got: %v", err)
+		}
+	})
+
+	t.Run("after close returns ErrScopeClosed", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, newTestLogger)
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		_ = s.Close()
+
+		if _, err := Resolve[*testLogger](s); !errors.Is(err, ErrScopeClosed) {
+			t.Fatalf("expected ErrScopeClosed, got: %v", err)
+		}
+		if _, err := ResolveNamed[*testLogger](s, "log"); !errors.Is(err, ErrScopeClosed) {
+			t.Fatalf("expected ErrScopeClosed, got: %v", err)
+		}
+	})
+}
+
+func TestScope_Close(t *testing.T) {
+	t.Run("closes scoped instances in reverse dependency order", func(t *testing.T) {
+		c := New()
+		var order []string
+		mustRegister(t, c, newTestLogger)
+		mustRegister(t, c, func(log *testLogger) *testUnitOfWork {
+			return &testUnitOfWork{Logger: log, Name: "uow", Order: &order}
+		}, WithLifetime(Scoped))
+		mustRegister(t, c, func(u *testUnitOfWork) *testRequestHandler {
+			return &testRequestHandler{UoW: u, Order: &order}
+		}, WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		h, err := Resolve[*testRequestHandler](s)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := s.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !h.Closed || !h.UoW.Closed {
+			t.Fatal("scoped instances should be closed")
+		}
+		if len(order) != 2 || order[0] != "handler" || order[1] != "uow" {
+			t.Fatalf("expected [handler uow], got %v", order)
+		}
+	})
+
+	t.Run("does not close singletons or transients", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() *testClosable { return &testClosable{Name: "singleton"} })
+		mustRegister(t, c, func() *testUnitOfWork { return &testUnitOfWork{} }, WithLifetime(Transient))
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		single, _ := Resolve[*testClosable](s)
+		trans, _ := Resolve[*testUnitOfWork](s)
+
+		if err := s.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if single.Closed || trans.Closed {
+			t.Fatal("scope should only close its scoped instances")
+		}
+	})
+
+	t.Run("collects close errors", func(t *testing.T) {
+		c := New()
+		mustRegister(t, c, func() *testFailCloser { return &testFailCloser{} }, WithLifetime(Scoped))
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		_, _ = Resolve[*testFailCloser](s)
+
+		err := s.Close()
+		if err == nil || !strings.Contains(err.Error(), "close failed") {
+			t.Fatalf("expected 'close failed' in error, got: %v", err)
+		}
+	})
+
+	t.Run("second call returns ErrScopeClosed", func(t *testing.T) {
+		c := New()
+		mustBuild(t, c)
+
+		s := c.NewScope()
+		_ = s.Close()
+		if err := s.Close(); !errors.Is(err, ErrScopeClosed) {
+			t.Fatalf("expected ErrScopeClosed, got: %v", err)
+		}
+	})
+}
+
+func TestScope_Concurrent(t *testing.T) {
+	c := New()
+	mustRegister(t, c, newTestLogger)
+	mustRegister(t, c, newTestUnitOfWork, WithLifetime(Scoped))
+	mustBuild(t, c)
+
+	s := c.NewScope()
+
+	const goroutines = 50
+	var wg sync.WaitGroup
+	results := make(chan *testUnitOfWork, goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u, err := Resolve[*testUnitOfWork](s)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			results <- u
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+
+	first := <-results
+	for u := range results {
+		if u != first {
+			t.Fatal("concurrent resolutions in one scope should share the instance")
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package oak
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// funcName returns the fully qualified name of the function fn, such as
+// github.com/acme/app/db.NewPool, or "" if it is unknown.
+func funcName(fn reflect.Value) string {
+	f := runtime.FuncForPC(fn.Pointer())
+	if f == nil {
+		return ""
+	}
+	return f.Name()
+}
+
+// site describes where p was registered for error messages, as
+// "db.NewPool at wire.go:42". The constructor's import path and the file's
+// directory are left out for brevity; [ProviderInfo] has them in full.
+func (p *provider) site() string {
+	name := p.funcName
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if p.file == "" {
+		return name
+	}
+	return fmt.Sprintf("%s at %s:%d", name, filepath.Base(p.file), p.line)
+}
@@ -0,0 +1,75 @@
+package oak
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// here returns the file and line of the statement following its call.
+func here() (string, int) {
+	_, file, line, _ := runtime.Caller(1)
+	return file, line + 1
+}
+
+func TestRegistrationSites(t *testing.T) {
+	t.Run("duplicate names both registrations", func(t *testing.T) {
+		c := New()
+		_, first := here()
+		if err := c.Register(newTestLogger); err != nil {
+			t.Fatal(err)
+		}
+		file, second := here()
+		err := c.Register(func() *testLogger { return nil })
+
+		want := fmt.Sprintf("*oak.testLogger registered by oak.TestRegistrationSites.func1.1 at %s:%d, already registered by oak.newTestLogger at %s:%d",
+			filepath.Base(file), second, filepath.Base(file), first)
+		if !errors.Is(err, ErrDuplicateProvider) || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q, got: %v", want, err)
+		}
+	})
+
+	t.Run("duplicate named provider", func(t *testing.T) {
+		c := New()
+		if err := c.RegisterNamed("cfg", newTestConfig); err != nil {
+			t.Fatal(err)
+		}
+		err := c.RegisterNamed("cfg", newTestConfig)
+		if !errors.Is(err, ErrDuplicateProvider) || !strings.Contains(err.Error(), `named "cfg" registered by oak.newTestConfig at source_test.go:`) {
+			t.Fatalf("expected both sites, got: %v", err)
+		}
+	})
+
+	t.Run("missing dependency names the dependent", func(t *testing.T) {
+		c := New()
+		file, line := here()
+		if err := c.Register(newTestOrderService); err != nil {
+			t.Fatal(err)
+		}
+
+		err := c.Build()
+		want := fmt.Sprintf("(required by oak.newTestOrderService at %s:%d)", filepath.Base(file), line)
+		if !errors.Is(err, ErrProviderNotFound) || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q, got: %v", want, err)
+		}
+	})
+
+	t.Run("constructor failure names the constructor", func(t *testing.T) {
+		c := New()
+		file, line := here()
+		if err := c.Register(newTestFailingConfig); err != nil {
+			t.Fatal(err)
+		}
+
+		err := c.Build()
+		want := fmt.Sprintf("constructing *oak.testConfig (oak.newTestFailingConfig at %s:%d): ", filepath.Base(file), line)
+		if err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Fatalf("expected %q prefix, got: %v", want, err)
+		}
+	})
+}
+
+func newTestFailingConfig() (*testConfig, error) { return nil, errors.New("boom") }